@@ -103,6 +103,101 @@ func bindNamed(tz *time.Location, query string, args ...interface{}) (_ string,
 	return query, nil
 }
 
+var bindParametersRe = regexp.MustCompile(`\{\s*([a-zA-Z_][a-zA-Z0-9_]*)\s*:\s*([^{}]+?)\s*\}`)
+
+// bindParameters substitutes the {name:Type} placeholders for the servers which do not support query parameters.
+func bindParameters(query string, params Parameters) (string, error) {
+	unbind := make(map[string]struct{})
+	query = bindParametersRe.ReplaceAllStringFunc(query, func(placeholder string) string {
+		var (
+			match     = bindParametersRe.FindStringSubmatch(placeholder)
+			name, typ = match[1], match[2]
+		)
+		value, found := params[name]
+		if !found {
+			unbind[name] = struct{}{}
+			return ""
+		}
+		if typ == "Identifier" {
+			return "`" + strings.NewReplacer("`", "\\`", `\`, `\\`).Replace(value) + "`"
+		}
+		return fmt.Sprintf("CAST(%s AS %s)", format(time.UTC, value), typ)
+	})
+	for param := range unbind {
+		return "", fmt.Errorf("have no value for %q param", param)
+	}
+	return query, nil
+}
+
+// bindQueryParameters moves the named arguments of the {name:Type} placeholders to the query parameters
+// (sent to the server along with the query), the other arguments are left to bind.
+func bindQueryParameters(tz *time.Location, query string, params Parameters, args ...interface{}) (Parameters, []interface{}, error) {
+	placeholders := make(map[string]struct{})
+	for _, match := range bindParametersRe.FindAllStringSubmatch(query, -1) {
+		placeholders[match[1]] = struct{}{}
+	}
+	if len(placeholders) == 0 {
+		return params, args, nil
+	}
+	var (
+		rest       = make([]interface{}, 0, len(args))
+		parameters = make(Parameters, len(params)+len(args))
+	)
+	for k, v := range params {
+		parameters[k] = v
+	}
+	for _, arg := range args {
+		named, ok := arg.(driver.NamedValue)
+		if _, found := placeholders[named.Name]; !ok || !found {
+			rest = append(rest, arg)
+			continue
+		}
+		value := named.Value
+		if fn, ok := value.(std_driver.Valuer); ok {
+			var err error
+			if value, err = fn.Value(); err != nil {
+				return nil, nil, err
+			}
+		}
+		parameters[named.Name] = formatParameter(tz, value, false)
+	}
+	return parameters, rest, nil
+}
+
+// formatParameter formats the value in the text format of the query parameters,
+// the strings and the dates are quoted in the arrays only.
+func formatParameter(tz *time.Location, v interface{}, quoted bool) string {
+	quote := func(v string) string {
+		if !quoted {
+			// the escaped text format
+			return strings.NewReplacer(`\`, `\\`, "\t", `\t`, "\n", `\n`).Replace(v)
+		}
+		return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(v) + "'"
+	}
+	switch v := v.(type) {
+	case nil:
+		if quoted {
+			return "NULL"
+		}
+		return `\N`
+	case string:
+		return quote(v)
+	case time.Time:
+		return quote(v.In(tz).Format("2006-01-02 15:04:05"))
+	case fmt.Stringer:
+		return quote(v.String())
+	}
+	switch v := reflect.ValueOf(v); v.Kind() {
+	case reflect.Slice:
+		values := make([]string, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			values = append(values, formatParameter(tz, v.Index(i).Interface(), true))
+		}
+		return "[" + strings.Join(values, ", ") + "]"
+	}
+	return fmt.Sprint(v)
+}
+
 func format(tz *time.Location, v interface{}) string {
 	quote := func(v string) string {
 		return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(v) + "'"
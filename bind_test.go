@@ -152,3 +152,47 @@ func BenchmarkBindNamed(b *testing.B) {
 		}
 	}
 }
+
+func TestBindParameters(t *testing.T) {
+	query, err := bindParameters("SELECT {a:UInt8}, { b : Array(String) }, {a:UInt8} FROM {t:Identifier}", Parameters{
+		"a": "42",
+		"b": `['it''s']`,
+		"t": "my`table",
+	})
+	if assert.NoError(t, err) {
+		assert.Equal(t, "SELECT CAST('42' AS UInt8), CAST('[\\'it\\'\\'s\\']' AS Array(String)), CAST('42' AS UInt8) FROM `my\\`table`", query)
+	}
+	if _, err := bindParameters("SELECT {a:UInt8}", Parameters{}); assert.Error(t, err) {
+		assert.Equal(t, `have no value for "a" param`, err.Error())
+	}
+}
+
+func TestBindQueryParameters(t *testing.T) {
+	var (
+		query = "INSERT INTO t VALUES ({id:UInt64}, {name:String}, {tags:Array(String)}, {ts:DateTime}, @other)"
+		ts    = time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC)
+	)
+	params, args, err := bindQueryParameters(time.UTC, query, Parameters{"id": "1", "extra": "x"},
+		Named("id", uint64(42)),
+		Named("name", "a\tb\\c"),
+		Named("tags", []string{"it's", "b"}),
+		Named("ts", ts),
+		Named("other", 1),
+	)
+	if assert.NoError(t, err) {
+		assert.Equal(t, Parameters{
+			"id":    "42",
+			"extra": "x",
+			"name":  `a\tb\\c`,
+			"tags":  `['it\'s', 'b']`,
+			"ts":    "2022-01-02 03:04:05",
+		}, params)
+		assert.Equal(t, []interface{}{Named("other", 1)}, args)
+	}
+	// the queries without placeholders are bound as before
+	params, args, err = bindQueryParameters(time.UTC, "INSERT INTO t VALUES ($1)", nil, 1)
+	if assert.NoError(t, err) {
+		assert.Nil(t, params)
+		assert.Equal(t, []interface{}{1}, args)
+	}
+}
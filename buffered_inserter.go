@@ -0,0 +1,194 @@
+package clickhouse
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
+)
+
+var ErrBufferedInserterClosed = errors.New("clickhouse: buffered inserter is closed")
+
+// BufferedInserterOptions are the limits of the buffers of the BufferedInserter.
+type BufferedInserterOptions struct {
+	MaxRows       int           // the rows of a query are sent once there are MaxRows of them, default 10000
+	FlushInterval time.Duration // all the buffered rows are sent every FlushInterval, default 1s
+	// OnFlush is called with the outcome of every sent batch, the rows of a failed batch are dropped.
+	OnFlush func(query string, rows int, err error)
+}
+
+func (o *BufferedInserterOptions) setDefaults() {
+	if o.MaxRows <= 0 {
+		o.MaxRows = 10000
+	}
+	if o.FlushInterval <= 0 {
+		o.FlushInterval = time.Second
+	}
+}
+
+// BufferedInserter collects the rows inserted by many goroutines and sends them in a batch per query.
+// The connections of the pool are only used when the rows are sent.
+type BufferedInserter struct {
+	ctx     context.Context
+	conn    driver.Conn
+	opt     BufferedInserterOptions
+	mutex   sync.Mutex
+	buffers map[string][]bufferedRow
+	// full are the buffers of MaxRows rows, they are sent by the flush goroutine which is woken by flush
+	full   []bufferedBatch
+	flush  chan struct{}
+	closed bool
+	done   chan struct{}
+	wg     sync.WaitGroup
+}
+
+type bufferedBatch struct {
+	query string
+	rows  []bufferedRow
+}
+
+type bufferedRow struct {
+	values []interface{}
+	// structure is the value of InsertStruct
+	structure interface{}
+}
+
+// NewBufferedInserter starts a BufferedInserter, the batches are prepared with the options of the context.
+func NewBufferedInserter(ctx context.Context, conn driver.Conn, opt BufferedInserterOptions) *BufferedInserter {
+	opt.setDefaults()
+	b := &BufferedInserter{
+		ctx:     ctx,
+		conn:    conn,
+		opt:     opt,
+		buffers: make(map[string][]bufferedRow),
+		flush:   make(chan struct{}, 1),
+		done:    make(chan struct{}),
+	}
+	b.wg.Add(1)
+	go b.flushEvery(opt.FlushInterval)
+	return b
+}
+
+// Insert adds a row to the buffer of the INSERT query. The errors of the batches are reported by OnFlush.
+// A full buffer is sent in the background, Insert does not wait for the query.
+func (b *BufferedInserter) Insert(query string, v ...interface{}) error {
+	return b.add(query, bufferedRow{
+		values: append([]interface{}(nil), v...),
+	})
+}
+
+// InsertStruct adds a struct to the buffer of the INSERT query, it is appended with AppendStruct.
+func (b *BufferedInserter) InsertStruct(query string, v interface{}) error {
+	return b.add(query, bufferedRow{
+		structure: v,
+	})
+}
+
+func (b *BufferedInserter) add(query string, row bufferedRow) error {
+	b.mutex.Lock()
+	if b.closed {
+		b.mutex.Unlock()
+		return ErrBufferedInserterClosed
+	}
+	rows := append(b.buffers[query], row)
+	if len(rows) < b.opt.MaxRows {
+		b.buffers[query] = rows
+		b.mutex.Unlock()
+		return nil
+	}
+	delete(b.buffers, query)
+	b.full = append(b.full, bufferedBatch{
+		query: query,
+		rows:  rows,
+	})
+	b.mutex.Unlock()
+	select {
+	case b.flush <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Flush sends all the buffered rows, the full buffers first.
+func (b *BufferedInserter) Flush() {
+	b.mutex.Lock()
+	full, buffers := b.full, b.buffers
+	b.full, b.buffers = nil, make(map[string][]bufferedRow)
+	b.mutex.Unlock()
+	for _, batch := range full {
+		b.send(batch.query, batch.rows)
+	}
+	for query, rows := range buffers {
+		b.send(query, rows)
+	}
+}
+
+// flushFull sends the full buffers.
+func (b *BufferedInserter) flushFull() {
+	b.mutex.Lock()
+	full := b.full
+	b.full = nil
+	b.mutex.Unlock()
+	for _, batch := range full {
+		b.send(batch.query, batch.rows)
+	}
+}
+
+// Close sends the buffered rows and stops the BufferedInserter, the connection is not closed.
+func (b *BufferedInserter) Close() error {
+	b.mutex.Lock()
+	if b.closed {
+		b.mutex.Unlock()
+		return ErrBufferedInserterClosed
+	}
+	b.closed = true
+	b.mutex.Unlock()
+	close(b.done)
+	b.wg.Wait()
+	b.Flush()
+	return nil
+}
+
+func (b *BufferedInserter) flushEvery(interval time.Duration) {
+	defer b.wg.Done()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-b.done:
+			return
+		case <-b.flush:
+			b.flushFull()
+		case <-ticker.C:
+			b.Flush()
+		}
+	}
+}
+
+func (b *BufferedInserter) send(query string, rows []bufferedRow) {
+	err := b.sendBatch(query, rows)
+	if b.opt.OnFlush != nil {
+		b.opt.OnFlush(query, len(rows), err)
+	}
+}
+
+func (b *BufferedInserter) sendBatch(query string, rows []bufferedRow) error {
+	batch, err := b.conn.PrepareBatch(b.ctx, query)
+	if err != nil {
+		return err
+	}
+	for _, row := range rows {
+		if row.structure != nil {
+			err = batch.AppendStruct(row.structure)
+		} else {
+			err = batch.Append(row.values...)
+		}
+		if err != nil {
+			batch.Abort()
+			return err
+		}
+	}
+	return batch.Send()
+}
@@ -0,0 +1,121 @@
+package clickhouse
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeBatchConn struct {
+	driver.Conn
+	mutex sync.Mutex
+	sent  map[string]int
+	// wait blocks Send until it is closed
+	wait chan struct{}
+}
+
+func (c *fakeBatchConn) PrepareBatch(ctx context.Context, query string) (driver.Batch, error) {
+	return &fakeBatch{conn: c, query: query}, nil
+}
+
+type fakeBatch struct {
+	driver.Batch
+	conn  *fakeBatchConn
+	query string
+	rows  int
+}
+
+func (b *fakeBatch) Append(v ...interface{}) error {
+	if len(v) == 0 {
+		return errors.New("empty row")
+	}
+	b.rows++
+	return nil
+}
+
+func (b *fakeBatch) AppendStruct(v interface{}) error { return b.Append(v) }
+func (b *fakeBatch) Abort() error                     { return nil }
+func (b *fakeBatch) Send() error {
+	if b.conn.wait != nil {
+		<-b.conn.wait
+	}
+	b.conn.mutex.Lock()
+	defer b.conn.mutex.Unlock()
+	b.conn.sent[b.query] += b.rows
+	return nil
+}
+
+func TestBufferedInserter(t *testing.T) {
+	var (
+		conn    = &fakeBatchConn{sent: make(map[string]int)}
+		mutex   sync.Mutex
+		flushes []int
+		errs    int
+	)
+	inserter := NewBufferedInserter(context.Background(), conn, BufferedInserterOptions{
+		MaxRows:       10,
+		FlushInterval: time.Hour,
+		OnFlush: func(query string, rows int, err error) {
+			mutex.Lock()
+			defer mutex.Unlock()
+			if err != nil {
+				errs++
+				return
+			}
+			flushes = append(flushes, rows)
+		},
+	})
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 5; j++ {
+				assert.NoError(t, inserter.Insert("INSERT INTO a", j))
+				assert.NoError(t, inserter.InsertStruct("INSERT INTO b", &struct{ Col1 int }{j}))
+			}
+		}()
+	}
+	wg.Wait()
+	// the rows of the failed batch are dropped
+	assert.NoError(t, inserter.Insert("INSERT INTO c"))
+	if assert.NoError(t, inserter.Close()) {
+		assert.Equal(t, map[string]int{"INSERT INTO a": 25, "INSERT INTO b": 25}, conn.sent)
+		assert.Equal(t, []int{10, 10, 10, 10}, flushes[:4])
+		assert.Len(t, flushes, 6)
+		assert.Equal(t, 1, errs)
+	}
+	assert.Equal(t, ErrBufferedInserterClosed, inserter.Insert("INSERT INTO a", 1))
+}
+
+func TestBufferedInserterFullBuffer(t *testing.T) {
+	var (
+		conn = &fakeBatchConn{
+			sent: make(map[string]int),
+			wait: make(chan struct{}),
+		}
+		flushed  = make(chan int, 2)
+		inserter = NewBufferedInserter(context.Background(), conn, BufferedInserterOptions{
+			MaxRows:       2,
+			FlushInterval: time.Hour,
+			OnFlush: func(query string, rows int, err error) {
+				flushed <- rows
+			},
+		})
+	)
+	// the full buffer is sent in the background, the inserts go on while its Send is blocked
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, inserter.Insert("INSERT INTO a", i))
+	}
+	assert.Len(t, flushed, 0)
+	close(conn.wait)
+	if assert.Equal(t, 2, <-flushed) && assert.NoError(t, inserter.Close()) {
+		assert.Equal(t, 1, <-flushed)
+		assert.Equal(t, map[string]int{"INSERT INTO a": 3}, conn.sent)
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -19,13 +21,16 @@ type (
 	Exception     = proto.Exception
 	ProfileInfo   = proto.ProfileInfo
 	ServerVersion = proto.ServerHandshake
+	TableStatus   = proto.TableStatus
 )
 
 var (
 	ErrBatchAlreadySent               = errors.New("clickhouse: batch has already been sent")
-	ErrAcquireConnTimeout             = errors.New("clickhouse: acquire conn timeout. you can increase the number of max open conn or the dial timeout")
+	ErrAcquireConnTimeout             = errors.New("clickhouse: acquire conn timeout. you can increase the number of max open conn or the acquire timeout")
 	ErrUnsupportedServerRevision      = errors.New("clickhouse: unsupported server revision")
 	ErrBindMixedNamedAndNumericParams = errors.New("clickhouse [bind]: mixed named and numeric parameters")
+	ErrSessionReleased                = errors.New("clickhouse: session has already been released")
+	ErrMixedRowsAndBlocks             = errors.New("clickhouse: rows and blocks can not be read from the same result")
 )
 
 type OpError struct {
@@ -54,18 +59,30 @@ func (e *OpError) Error() string {
 
 func Open(opt *Options) (driver.Conn, error) {
 	opt.setDefaults()
-	return &clickhouse{
-		opt:  opt,
-		idle: make(chan *connect, opt.MaxIdleConns),
-		open: make(chan struct{}, opt.MaxOpenConns),
-	}, nil
+	ch := &clickhouse{
+		opt:   opt,
+		idle:  make(chan *connect, opt.MaxIdleConns),
+		open:  make(chan struct{}, opt.MaxOpenConns),
+		done:  make(chan struct{}),
+		hosts: newHosts(opt),
+		stats: &poolStats{},
+	}
+	ch.maintenance.Add(1)
+	go ch.maintain()
+	return ch, nil
 }
 
 type clickhouse struct {
 	opt    *Options
 	idle   chan *connect
 	open   chan struct{}
+	done   chan struct{}
+	hosts  *hosts
+	stats  *poolStats
+	close  sync.Once
 	connID int64
+	// maintenance waits for the goroutine checking the idle connections
+	maintenance sync.WaitGroup
 }
 
 func (ch *clickhouse) ServerVersion() (*driver.ServerVersion, error) {
@@ -81,13 +98,14 @@ func (ch *clickhouse) ServerVersion() (*driver.ServerVersion, error) {
 	return &conn.server, nil
 }
 
+// Query takes a connection from the pool for the time of the result, the connection goes back to the pool
+// once the rows are read or closed.
 func (ch *clickhouse) Query(ctx context.Context, query string, args ...interface{}) (rows driver.Rows, err error) {
 	conn, err := ch.acquire(ctx)
 	if err != nil {
 		return nil, err
 	}
-	defer ch.release(conn)
-	return conn.query(ctx, query, args...)
+	return conn.query(ctx, ch.release, query, args...)
 }
 
 func (ch *clickhouse) QueryRow(ctx context.Context, query string, args ...interface{}) (rows driver.Row) {
@@ -97,8 +115,7 @@ func (ch *clickhouse) QueryRow(ctx context.Context, query string, args ...interf
 			err: err,
 		}
 	}
-	defer ch.release(conn)
-	return conn.queryRow(ctx, query, args...)
+	return conn.queryRow(ctx, ch.release, query, args...)
 }
 
 func (ch *clickhouse) Exec(ctx context.Context, query string, args ...interface{}) error {
@@ -110,13 +127,43 @@ func (ch *clickhouse) Exec(ctx context.Context, query string, args ...interface{
 	return conn.exec(ctx, query, args...)
 }
 
+// AsyncInsert runs the INSERT with the asynchronous inserts of the server: the rows of many small INSERTs are collected
+// in the buffer of the server and written together. With wait the call returns once the rows are written to the table,
+// without it as soon as they are in the buffer. The named arguments of the {name:Type} placeholders are sent
+// as the query parameters (see WithParameters), the other arguments are bound into the query text.
+func (ch *clickhouse) AsyncInsert(ctx context.Context, query string, wait bool, args ...interface{}) error {
+	conn, err := ch.acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer ch.release(conn)
+	return conn.asyncInsert(ctx, query, wait, args...)
+}
+
 func (ch *clickhouse) PrepareBatch(ctx context.Context, query string) (driver.Batch, error) {
 	conn, err := ch.acquire(ctx)
 	if err != nil {
 		return nil, err
 	}
-	defer ch.release(conn)
-	return conn.prepareBatch(ctx, query, ch.release)
+	batch, err := conn.prepareBatch(ctx, query, ch.release)
+	if err != nil {
+		return nil, err
+	}
+	batch.acquire = ch.acquire
+	return batch, nil
+}
+
+// Acquire takes a connection from the pool for the session state of the server: the settings changed by SET,
+// the current database and the temporary tables. The connection goes back to the pool on Release.
+func (ch *clickhouse) Acquire(ctx context.Context) (driver.Session, error) {
+	conn, err := ch.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &session{
+		ch:   ch,
+		conn: conn,
+	}, nil
 }
 
 func (ch *clickhouse) Ping(ctx context.Context) error {
@@ -128,77 +175,277 @@ func (ch *clickhouse) Ping(ctx context.Context) error {
 	return nil
 }
 
+// TablesStatus returns the status of the tables ("table" in the database of the connection or "database.table")
+// on the server of a connection from the pool. The replication delay of the replicated tables can be used for health checks.
+func (ch *clickhouse) TablesStatus(ctx context.Context, tables ...string) ([]driver.TableStatus, error) {
+	conn, err := ch.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer ch.release(conn)
+	names := make([]proto.QualifiedTableName, 0, len(tables))
+	for _, table := range tables {
+		name := proto.QualifiedTableName{
+			Database: ch.opt.Auth.Database,
+			Table:    table,
+		}
+		if n := strings.Index(table, "."); n != -1 {
+			name.Database, name.Table = table[:n], table[n+1:]
+		}
+		if len(name.Database) == 0 {
+			name.Database = "default"
+		}
+		names = append(names, name)
+	}
+	return conn.tablesStatus(ctx, names)
+}
+
+// poolStats are the counters of the pool, they are updated atomically.
+type poolStats struct {
+	open              int64
+	waitCount         int64
+	waitDuration      int64
+	acquireTimeouts   int64
+	maxIdleClosed     int64
+	maxIdleTimeClosed int64
+	maxLifetimeClosed int64
+	errorClosed       int64
+	sessionClosed     int64
+	io                ioStats
+}
+
 func (ch *clickhouse) Stats() driver.Stats {
 	return driver.Stats{
-		Open:         len(ch.open),
-		Idle:         len(ch.idle),
-		MaxOpenConns: cap(ch.open),
-		MaxIdleConns: cap(ch.idle),
+		Open:              int(atomic.LoadInt64(&ch.stats.open)),
+		Idle:              len(ch.idle),
+		InUse:             len(ch.open),
+		MaxOpenConns:      cap(ch.open),
+		MaxIdleConns:      cap(ch.idle),
+		WaitCount:         atomic.LoadInt64(&ch.stats.waitCount),
+		WaitDuration:      time.Duration(atomic.LoadInt64(&ch.stats.waitDuration)),
+		AcquireTimeouts:   atomic.LoadInt64(&ch.stats.acquireTimeouts),
+		MaxIdleClosed:     atomic.LoadInt64(&ch.stats.maxIdleClosed),
+		MaxIdleTimeClosed: atomic.LoadInt64(&ch.stats.maxIdleTimeClosed),
+		MaxLifetimeClosed: atomic.LoadInt64(&ch.stats.maxLifetimeClosed),
+		ErrorClosed:       atomic.LoadInt64(&ch.stats.errorClosed),
+		SessionClosed:     atomic.LoadInt64(&ch.stats.sessionClosed),
+		BytesSent:         atomic.LoadInt64(&ch.stats.io.sent),
+		BytesReceived:     atomic.LoadInt64(&ch.stats.io.received),
+		Hosts:             ch.hosts.stats(),
 	}
 }
 
-func (ch *clickhouse) dial() (conn *connect, err error) {
+func (ch *clickhouse) dial(ctx context.Context) (conn *connect, err error) {
 	connID := int(atomic.AddInt64(&ch.connID, 1))
-	for num := range ch.opt.Addr {
-		if ch.opt.ConnOpenStrategy == ConnOpenRoundRobin {
-			num = int(connID) % len(ch.opt.Addr)
-		}
-		if conn, err = dial(ch.opt.Addr[num], connID, ch.opt); err == nil {
+	for _, host := range ch.hosts.order(ch.opt.ConnOpenStrategy, connID) {
+		if conn, err = dial(ctx, host.addr, connID, ch.opt, &ch.stats.io); err == nil {
+			ch.hosts.connected(host)
+			atomic.AddInt64(&ch.stats.open, 1)
+			conn.onClose = func() {
+				ch.hosts.closed(host)
+				atomic.AddInt64(&ch.stats.open, -1)
+			}
 			return conn, nil
 		}
+		// the host is not to blame when the caller gave up
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		ch.hosts.failed(host, err)
 	}
 	return nil, err
 }
 
+// acquire waits for a connection of the pool until AcquireTimeout or the context is done,
+// the connection is dialed with the context.
 func (ch *clickhouse) acquire(ctx context.Context) (conn *connect, err error) {
-	timer := time.NewTimer(ch.opt.DialTimeout)
-	defer timer.Stop()
 	select {
 	case <-ctx.Done():
 		return nil, ctx.Err()
 	default:
 	}
 	select {
-	case <-timer.C:
-		return nil, ErrAcquireConnTimeout
 	case ch.open <- struct{}{}:
+	default:
+		timer := time.NewTimer(ch.opt.AcquireTimeout)
+		defer timer.Stop()
+		start := time.Now()
+		atomic.AddInt64(&ch.stats.waitCount, 1)
+		select {
+		case <-ctx.Done():
+			atomic.AddInt64(&ch.stats.waitDuration, int64(time.Since(start)))
+			return nil, ctx.Err()
+		case <-timer.C:
+			atomic.AddInt64(&ch.stats.waitDuration, int64(time.Since(start)))
+			atomic.AddInt64(&ch.stats.acquireTimeouts, 1)
+			return nil, ErrAcquireConnTimeout
+		case ch.open <- struct{}{}:
+			atomic.AddInt64(&ch.stats.waitDuration, int64(time.Since(start)))
+		}
 	}
+	defer func() {
+		// the connection could not be dialed, its place in the pool is free
+		if err != nil {
+			<-ch.open
+		}
+	}()
 	select {
-	case <-timer.C:
-		return nil, ErrAcquireConnTimeout
 	case conn := <-ch.idle:
+		if ch.retireExpired(conn) {
+			return ch.dial(ctx)
+		}
 		if conn.isBad() {
-			conn.close()
-			return ch.dial()
+			ch.retire(conn, &ch.stats.errorClosed)
+			return ch.dial(ctx)
 		}
 		conn.released = false
 		return conn, nil
 	default:
 	}
-	return ch.dial()
+	return ch.dial(ctx)
 }
 
 func (ch *clickhouse) release(conn *connect) {
-	if conn.released {
+	if !ch.free(conn) {
+		return
+	}
+	switch {
+	case conn.err != nil, conn.closed:
+		ch.retire(conn, &ch.stats.errorClosed)
 		return
+	case time.Since(conn.connectedAt) >= ch.opt.ConnMaxLifetime:
+		ch.retire(conn, &ch.stats.maxLifetimeClosed)
+		return
+	}
+	conn.releasedAt = time.Now()
+	select {
+	case ch.idle <- conn:
+	default:
+		ch.retire(conn, &ch.stats.maxIdleClosed)
+	}
+}
+
+// free gives back the slot of the connection, it is false if the connection has already been released.
+func (ch *clickhouse) free(conn *connect) bool {
+	if conn.released {
+		return false
 	}
 	conn.released = true
 	select {
 	case <-ch.open:
 	default:
 	}
-	if conn.err != nil || time.Since(conn.connectedAt) >= ch.opt.ConnMaxLifetime {
-		conn.close()
-		return
+	return true
+}
+
+// retire closes the connection and counts the reason.
+func (ch *clickhouse) retire(conn *connect, reason *int64) {
+	atomic.AddInt64(reason, 1)
+	conn.close()
+}
+
+// retireExpired closes the connection which has outlived ConnMaxLifetime or has been idle for longer than ConnMaxIdleTime.
+func (ch *clickhouse) retireExpired(conn *connect) bool {
+	switch {
+	case time.Since(conn.connectedAt) >= ch.opt.ConnMaxLifetime:
+		ch.retire(conn, &ch.stats.maxLifetimeClosed)
+	case ch.opt.ConnMaxIdleTime > 0 && time.Since(conn.releasedAt) >= ch.opt.ConnMaxIdleTime:
+		ch.retire(conn, &ch.stats.maxIdleTimeClosed)
+	default:
+		return false
+	}
+	return true
+}
+
+// maintain checks the idle connections every MaintenanceInterval until the pool is closed.
+func (ch *clickhouse) maintain() {
+	defer ch.maintenance.Done()
+	ticker := time.NewTicker(ch.opt.MaintenanceInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ch.done:
+			return
+		case <-ticker.C:
+			ch.checkIdle()
+			ch.fillIdle()
+		}
+	}
+}
+
+// checkIdle closes the expired and the broken idle connections and pings the others,
+// so the connections dropped by the server or a load balancer are not handed out.
+func (ch *clickhouse) checkIdle() {
+	for i, n := 0, len(ch.idle); i < n; i++ {
+		var conn *connect
+		select {
+		case conn = <-ch.idle:
+		default:
+			return
+		}
+		if ch.retireExpired(conn) {
+			continue
+		}
+		if conn.connCheck() != nil {
+			ch.retire(conn, &ch.stats.errorClosed)
+			continue
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), ch.opt.DialTimeout)
+		err := conn.ping(ctx)
+		cancel()
+		if err != nil {
+			ch.retire(conn, &ch.stats.errorClosed)
+			continue
+		}
+		conn.lastUsedIn = time.Now()
+		select {
+		case ch.idle <- conn:
+		default:
+			ch.retire(conn, &ch.stats.maxIdleClosed)
+		}
+	}
+}
+
+// fillIdle opens the connections up to MinIdleConns. A connection is dialed in a slot of the pool,
+// so the dialed connections and the ones in use stay within MaxOpenConns.
+func (ch *clickhouse) fillIdle() {
+	for len(ch.idle) < ch.opt.MinIdleConns && len(ch.idle)+len(ch.open) < cap(ch.open) {
+		select {
+		case <-ch.done:
+			return
+		case ch.open <- struct{}{}:
+		default:
+			return
+		}
+		ok := ch.dialIdle()
+		<-ch.open
+		if !ok {
+			return
+		}
+	}
+}
+
+// dialIdle dials a connection into the idle pool.
+func (ch *clickhouse) dialIdle() bool {
+	conn, err := ch.dial(context.Background())
+	if err != nil {
+		return false
 	}
+	conn.lastUsedIn, conn.releasedAt = time.Now(), time.Now()
 	select {
 	case ch.idle <- conn:
+		return true
 	default:
 		conn.close()
+		return false
 	}
 }
 
 func (ch *clickhouse) Close() error {
+	ch.close.Do(func() {
+		close(ch.done)
+	})
+	ch.maintenance.Wait()
 	for {
 		select {
 		case c := <-ch.idle:
@@ -0,0 +1,140 @@
+package clickhouse
+
+import (
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
+)
+
+// the backoff of a host which can not be dialed doubles with every failure
+const (
+	hostMinBackoff = time.Second
+	hostMaxBackoff = time.Minute
+)
+
+type host struct {
+	addr     string
+	priority int
+	// open is the number of the open connections of the host
+	open     int
+	failures int
+	// dialFailures is the number of all the failed dials
+	dialFailures int64
+	downUntil    time.Time
+	lastError    error
+}
+
+// hosts keeps the health of the addresses: after a failed dial an address is tried after the others
+// until its backoff is over, then it is probed again by the next dial.
+type hosts struct {
+	mutex sync.Mutex
+	list  []*host
+}
+
+func newHosts(opt *Options) *hosts {
+	h := &hosts{
+		list: make([]*host, 0, len(opt.Addr)),
+	}
+	for i, addr := range opt.Addr {
+		host := &host{
+			addr: addr,
+		}
+		if i < len(opt.AddrPriority) {
+			host.priority = opt.AddrPriority[i]
+		}
+		h.list = append(h.list, host)
+	}
+	return h
+}
+
+// order returns the hosts in the order the connection is dialed: the healthy hosts ordered by the strategy
+// followed by the hosts in backoff, the ones which have been down for a shorter time first.
+func (h *hosts) order(strategy ConnOpenStrategy, connID int) []*host {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	hosts := make([]*host, 0, len(h.list))
+	switch strategy {
+	case ConnOpenRoundRobin:
+		for i := range h.list {
+			hosts = append(hosts, h.list[(connID+i)%len(h.list)])
+		}
+	case ConnOpenRandom:
+		for _, i := range rand.Perm(len(h.list)) {
+			hosts = append(hosts, h.list[i])
+		}
+	case ConnOpenLeastConnections:
+		hosts = append(hosts, h.list...)
+		sort.SliceStable(hosts, func(i, j int) bool {
+			return hosts[i].open < hosts[j].open
+		})
+	case ConnOpenPriority:
+		hosts = append(hosts, h.list...)
+		sort.SliceStable(hosts, func(i, j int) bool {
+			if hosts[i].priority != hosts[j].priority {
+				return hosts[i].priority < hosts[j].priority
+			}
+			return hosts[i].open < hosts[j].open
+		})
+	default:
+		hosts = append(hosts, h.list...)
+	}
+	now := time.Now()
+	sort.SliceStable(hosts, func(i, j int) bool {
+		down := func(h *host) bool { return now.Before(h.downUntil) }
+		switch {
+		case down(hosts[i]) && down(hosts[j]):
+			return hosts[i].downUntil.Before(hosts[j].downUntil)
+		default:
+			return !down(hosts[i]) && down(hosts[j])
+		}
+	})
+	return hosts
+}
+
+func (h *hosts) connected(host *host) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	host.open++
+	host.failures, host.downUntil, host.lastError = 0, time.Time{}, nil
+}
+
+func (h *hosts) failed(host *host, err error) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	backoff := hostMinBackoff << uint(host.failures)
+	if backoff > hostMaxBackoff || backoff <= 0 {
+		backoff = hostMaxBackoff
+	}
+	host.failures++
+	host.dialFailures++
+	host.downUntil, host.lastError = time.Now().Add(backoff), err
+}
+
+func (h *hosts) closed(host *host) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	host.open--
+}
+
+func (h *hosts) stats() []driver.HostStats {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	now := time.Now()
+	stats := make([]driver.HostStats, 0, len(h.list))
+	for _, host := range h.list {
+		stats = append(stats, driver.HostStats{
+			Addr:         host.addr,
+			Priority:     host.priority,
+			Open:         host.open,
+			Healthy:      !now.Before(host.downUntil),
+			Failures:     host.failures,
+			DialFailures: host.dialFailures,
+			DownUntil:    host.downUntil,
+			LastError:    host.lastError,
+		})
+	}
+	return stats
+}
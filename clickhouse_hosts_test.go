@@ -0,0 +1,93 @@
+package clickhouse
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func hostsAddr(hosts []*host) []string {
+	addr := make([]string, 0, len(hosts))
+	for _, h := range hosts {
+		addr = append(addr, h.addr)
+	}
+	return addr
+}
+
+func TestHostsOrder(t *testing.T) {
+	h := newHosts(&Options{
+		Addr:         []string{"a", "b", "c"},
+		AddrPriority: []int{1, 0, 1},
+	})
+	assert.Equal(t, []string{"a", "b", "c"}, hostsAddr(h.order(ConnOpenInOrder, 1)))
+	assert.Equal(t, []string{"b", "c", "a"}, hostsAddr(h.order(ConnOpenRoundRobin, 1)))
+	assert.ElementsMatch(t, []string{"a", "b", "c"}, hostsAddr(h.order(ConnOpenRandom, 1)))
+	assert.Equal(t, []string{"b", "a", "c"}, hostsAddr(h.order(ConnOpenPriority, 1)))
+
+	h.connected(h.list[0])
+	h.connected(h.list[1])
+	assert.Equal(t, []string{"c", "a", "b"}, hostsAddr(h.order(ConnOpenLeastConnections, 1)))
+	assert.Equal(t, []string{"b", "c", "a"}, hostsAddr(h.order(ConnOpenPriority, 1)))
+	h.closed(h.list[0])
+	assert.Equal(t, []string{"a", "c", "b"}, hostsAddr(h.order(ConnOpenLeastConnections, 1)))
+}
+
+func TestHostsBackoff(t *testing.T) {
+	h := newHosts(&Options{
+		Addr: []string{"a", "b", "c"},
+	})
+	err := errors.New("connection refused")
+	h.failed(h.list[0], err)
+	h.failed(h.list[0], err)
+	h.failed(h.list[1], err)
+	// the hosts in backoff are dialed last, the one which is down for a shorter time first
+	assert.Equal(t, []string{"c", "b", "a"}, hostsAddr(h.order(ConnOpenInOrder, 1)))
+	stats := h.stats()
+	if assert.Len(t, stats, 3) {
+		assert.False(t, stats[0].Healthy)
+		assert.Equal(t, 2, stats[0].Failures)
+		assert.Equal(t, err, stats[0].LastError)
+		assert.WithinDuration(t, time.Now().Add(2*hostMinBackoff), stats[0].DownUntil, hostMinBackoff/2)
+		assert.True(t, stats[2].Healthy)
+	}
+	for i := 0; i < 20; i++ {
+		h.failed(h.list[2], err)
+	}
+	assert.WithinDuration(t, time.Now().Add(hostMaxBackoff), h.list[2].downUntil, time.Second)
+	// the backoff is over, the host is probed again
+	h.list[0].downUntil = time.Now()
+	assert.Equal(t, "a", h.order(ConnOpenInOrder, 1)[0].addr)
+	h.connected(h.list[0])
+	if stats := h.stats(); assert.True(t, stats[0].Healthy) {
+		assert.Equal(t, 0, stats[0].Failures)
+		assert.Equal(t, 1, stats[0].Open)
+		assert.NoError(t, stats[0].LastError)
+	}
+}
+
+func TestHostsCancelledConn(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var dialed []string
+	conn, err := Open(&Options{
+		Addr:                []string{"a", "b"},
+		ConnOpenStrategy:    ConnOpenLeastConnections,
+		MaintenanceInterval: time.Hour,
+		DialContext:         testCancelDial(cancel, &dialed),
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer conn.Close()
+	if err := conn.Exec(ctx, "SELECT 1"); assert.Equal(t, context.Canceled, err) && assert.Equal(t, []string{"a"}, dialed) {
+		// the closed connection of the cancelled query is not counted for its host
+		if stats := conn.Stats(); assert.Len(t, stats.Hosts, 2) {
+			assert.Equal(t, 0, stats.Hosts[0].Open)
+			assert.True(t, stats.Hosts[0].Healthy)
+		}
+		assert.Equal(t, []string{"a", "b"}, hostsAddr(conn.(*clickhouse).hosts.order(ConnOpenLeastConnections, 1)))
+	}
+}
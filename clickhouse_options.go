@@ -1,8 +1,10 @@
 package clickhouse
 
 import (
+	"context"
 	"crypto/tls"
 	"fmt"
+	"net"
 	"net/url"
 	"strconv"
 	"strings"
@@ -12,17 +14,25 @@ import (
 )
 
 var (
-	CompressionLZ4 compress.Method = compress.LZ4
+	CompressionLZ4   compress.Method = compress.LZ4
+	CompressionLZ4HC compress.Method = compress.LZ4HC
+	CompressionZSTD  compress.Method = compress.ZSTD
 )
 
 type Auth struct { // has_control_character
 	Database string
 	Username string
 	Password string
+	// QuotaKey is sent to the servers which support it after the handshake.
+	QuotaKey string
 }
 
 type Compression struct {
 	Method compress.Method
+	// Level is the compression level used by LZ4HC (1-9) and ZSTD (1-22). 0 means the default level of the method.
+	Level int
+	// BlockSize is the max size of the uncompressed data in a single compressed block. default 1 MiB
+	BlockSize int
 }
 
 type ConnOpenStrategy uint8
@@ -30,6 +40,11 @@ type ConnOpenStrategy uint8
 const (
 	ConnOpenInOrder ConnOpenStrategy = iota
 	ConnOpenRoundRobin
+	ConnOpenRandom
+	// ConnOpenLeastConnections dials the address with the fewest open connections.
+	ConnOpenLeastConnections
+	// ConnOpenPriority dials the addresses by AddrPriority, the addresses of the same priority by their open connections.
+	ConnOpenPriority
 )
 
 func ParseDSN(dsn string) (*Options, error) {
@@ -41,17 +56,24 @@ func ParseDSN(dsn string) (*Options, error) {
 }
 
 type Options struct {
-	TLS              *tls.Config
-	Addr             []string
-	Auth             Auth
-	Debug            bool
-	Settings         Settings
-	Compression      *Compression
-	DialTimeout      time.Duration // default 1 second
-	MaxOpenConns     int           // default MaxIdleConns + 5
-	MaxIdleConns     int           // default 5
-	ConnMaxLifetime  time.Duration // default 1 hour
-	ConnOpenStrategy ConnOpenStrategy
+	TLS                 *tls.Config
+	Addr                []string
+	AddrPriority        []int // the priorities of Addr for ConnOpenPriority, the lower the value the earlier the address is dialed
+	Auth                Auth
+	Debug               bool
+	Settings            Settings
+	Compression         *Compression
+	DialTimeout         time.Duration // default 1 second
+	AcquireTimeout      time.Duration // the wait for a connection of the pool, default DialTimeout
+	MaxOpenConns        int           // default MaxIdleConns + 5
+	MaxIdleConns        int           // default 5
+	ConnMaxLifetime     time.Duration // default 1 hour
+	ConnMaxIdleTime     time.Duration // default 0 (the idle connections are not closed)
+	MinIdleConns        int           // default 0
+	MaintenanceInterval time.Duration // the idle connections are checked every MaintenanceInterval, default 1 minute
+	ConnOpenStrategy    ConnOpenStrategy
+	// DialContext opens the connections instead of the TCP dialer, e.g. through a proxy or a Unix domain socket.
+	DialContext func(ctx context.Context, addr string) (net.Conn, error)
 }
 
 func (o *Options) fromDSN(in string) error {
@@ -68,9 +90,12 @@ func (o *Options) fromDSN(in string) error {
 	}
 	o.Addr = append(o.Addr, strings.Split(dsn.Host, ",")...)
 	var (
-		secure     bool
-		params     = dsn.Query()
-		skipVerify bool
+		secure            bool
+		params            = dsn.Query()
+		skipVerify        bool
+		compression       *Compression
+		compressLevel     int
+		compressBlockSize int
 	)
 	o.Auth.Database = strings.TrimPrefix(dsn.Path, "/")
 	for v := range params {
@@ -78,10 +103,33 @@ func (o *Options) fromDSN(in string) error {
 		case "debug":
 			o.Debug, _ = strconv.ParseBool(params.Get(v))
 		case "compress":
-			if on, _ := strconv.ParseBool(params.Get(v)); on {
-				o.Compression = &Compression{
+			switch method := strings.ToLower(params.Get(v)); method {
+			case "lz4":
+				compression = &Compression{
 					Method: CompressionLZ4,
 				}
+			case "lz4hc":
+				compression = &Compression{
+					Method: CompressionLZ4HC,
+				}
+			case "zstd":
+				compression = &Compression{
+					Method: CompressionZSTD,
+				}
+			default:
+				if on, _ := strconv.ParseBool(method); on {
+					compression = &Compression{
+						Method: CompressionLZ4,
+					}
+				}
+			}
+		case "compress_level":
+			if compressLevel, err = strconv.Atoi(params.Get(v)); err != nil {
+				return fmt.Errorf("clickhouse [dsn parse]: compress level: %s", err)
+			}
+		case "compress_block_size":
+			if compressBlockSize, err = strconv.Atoi(params.Get(v)); err != nil {
+				return fmt.Errorf("clickhouse [dsn parse]: compress block size: %s", err)
 			}
 		case "dial_timeout":
 			duration, err := time.ParseDuration(params.Get(v))
@@ -89,6 +137,14 @@ func (o *Options) fromDSN(in string) error {
 				return fmt.Errorf("clickhouse [dsn parse]: dial timeout: %s", err)
 			}
 			o.DialTimeout = duration
+		case "acquire_timeout":
+			duration, err := time.ParseDuration(params.Get(v))
+			if err != nil {
+				return fmt.Errorf("clickhouse [dsn parse]: acquire timeout: %s", err)
+			}
+			o.AcquireTimeout = duration
+		case "quota_key":
+			o.Auth.QuotaKey = params.Get(v)
 		case "secure":
 			secure = true
 		case "skip_verify":
@@ -99,6 +155,10 @@ func (o *Options) fromDSN(in string) error {
 				o.ConnOpenStrategy = ConnOpenInOrder
 			case "round_robin":
 				o.ConnOpenStrategy = ConnOpenRoundRobin
+			case "random":
+				o.ConnOpenStrategy = ConnOpenRandom
+			case "least_connections":
+				o.ConnOpenStrategy = ConnOpenLeastConnections
 			}
 		default:
 			switch p := strings.ToLower(params.Get(v)); p {
@@ -113,6 +173,10 @@ func (o *Options) fromDSN(in string) error {
 			}
 		}
 	}
+	if compression != nil {
+		compression.Level, compression.BlockSize = compressLevel, compressBlockSize
+		o.Compression = compression
+	}
 	if secure {
 		o.TLS = &tls.Config{
 			InsecureSkipVerify: skipVerify,
@@ -132,6 +196,9 @@ func (o *Options) setDefaults() {
 	if o.DialTimeout == 0 {
 		o.DialTimeout = time.Second
 	}
+	if o.AcquireTimeout <= 0 {
+		o.AcquireTimeout = o.DialTimeout
+	}
 	if o.MaxIdleConns <= 0 {
 		o.MaxIdleConns = 5
 	}
@@ -141,4 +208,10 @@ func (o *Options) setDefaults() {
 	if o.ConnMaxLifetime == 0 {
 		o.ConnMaxLifetime = time.Hour
 	}
+	if o.MinIdleConns > o.MaxIdleConns {
+		o.MinIdleConns = o.MaxIdleConns
+	}
+	if o.MaintenanceInterval <= 0 {
+		o.MaintenanceInterval = time.Minute
+	}
 }
@@ -0,0 +1,44 @@
+package clickhouse
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDSNCompression(t *testing.T) {
+	for _, asset := range []struct {
+		dsn      string
+		expected *Compression
+	}{
+		{dsn: "clickhouse://127.0.0.1:9000", expected: nil},
+		{dsn: "clickhouse://127.0.0.1:9000?compress=false", expected: nil},
+		{dsn: "clickhouse://127.0.0.1:9000?compress=true", expected: &Compression{Method: CompressionLZ4}},
+		{dsn: "clickhouse://127.0.0.1:9000?compress=lz4", expected: &Compression{Method: CompressionLZ4}},
+		{dsn: "clickhouse://127.0.0.1:9000?compress=zstd", expected: &Compression{Method: CompressionZSTD}},
+		{dsn: "clickhouse://127.0.0.1:9000?compress=ZSTD&compress_level=9", expected: &Compression{Method: CompressionZSTD, Level: 9}},
+		{dsn: "clickhouse://127.0.0.1:9000?compress=lz4hc&compress_level=6&compress_block_size=65536", expected: &Compression{Method: CompressionLZ4HC, Level: 6, BlockSize: 65536}},
+		{dsn: "clickhouse://127.0.0.1:9000?compress_level=9", expected: nil},
+	} {
+		if opt, err := ParseDSN(asset.dsn); assert.NoError(t, err) {
+			assert.Equal(t, asset.expected, opt.Compression, asset.dsn)
+		}
+	}
+	_, err := ParseDSN("clickhouse://127.0.0.1:9000?compress=zstd&compress_level=max")
+	assert.Error(t, err)
+}
+
+func TestParseDSNTimeouts(t *testing.T) {
+	if opt, err := ParseDSN("clickhouse://127.0.0.1:9000?dial_timeout=200ms&acquire_timeout=5s"); assert.NoError(t, err) {
+		assert.Equal(t, 200*time.Millisecond, opt.DialTimeout)
+		assert.Equal(t, 5*time.Second, opt.AcquireTimeout)
+	}
+	opt := &Options{
+		DialTimeout: 200 * time.Millisecond,
+	}
+	opt.setDefaults()
+	assert.Equal(t, 200*time.Millisecond, opt.AcquireTimeout)
+	_, err := ParseDSN("clickhouse://127.0.0.1:9000?acquire_timeout=5")
+	assert.Error(t, err)
+}
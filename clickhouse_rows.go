@@ -2,20 +2,37 @@ package clickhouse
 
 import (
 	"database/sql"
+	"fmt"
 	"io"
+	"reflect"
+	"time"
 
+	"github.com/ClickHouse/clickhouse-go/v2/lib/column"
+	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
 	"github.com/ClickHouse/clickhouse-go/v2/lib/proto"
 )
 
+// the result is read row by row with Next or block by block with NextBlock
+const (
+	readRows = iota + 1
+	readBlocks
+)
+
 type rows struct {
-	err     error
-	row     int
-	conn    *connect
-	block   *proto.Block
-	totals  *proto.Block
-	errors  chan error
-	stream  chan *proto.Block
+	err      error
+	row      int
+	reading  int
+	conn     *connect
+	block    *proto.Block
+	totals   *proto.Block
+	extremes *proto.Block
+	errors   chan error
+	stream   chan *proto.Block
+	// release gives the connection back once the result is read, it is called once
+	release func(*connect)
 	columns []string
+	// types are the columns of the header block, they describe the columns of the result
+	types []column.Interface
 }
 
 func (r *rows) Next() (result bool) {
@@ -24,7 +41,11 @@ func (r *rows) Next() (result bool) {
 			r.Close()
 		}
 	}()
-	if r.block == nil {
+	if r.reading == readBlocks {
+		r.err = ErrMixedRowsAndBlocks
+		return false
+	}
+	if r.reading = readRows; r.block == nil {
 		return false
 	}
 next:
@@ -32,7 +53,7 @@ next:
 		select {
 		case err := <-r.errors:
 			if err != nil {
-				r.err, r.conn.err = err, err
+				r.err = err
 				return false
 			}
 			goto next
@@ -40,8 +61,8 @@ next:
 			if block == nil || block.Rows() == 0 {
 				return false
 			}
-			if block.Packet == proto.ServerTotals {
-				r.row, r.block, r.totals = 0, nil, block
+			if r.keep(block) {
+				r.row, r.block = 0, nil
 				return false
 			}
 			r.row, r.block = 0, block
@@ -51,6 +72,69 @@ next:
 	return r.row <= r.block.Rows()
 }
 
+// NextBlock reads the next block of the result, a result is read either by Next or by NextBlock.
+func (r *rows) NextBlock() (result bool) {
+	defer func() {
+		if !result {
+			r.Close()
+		}
+	}()
+	if r.reading == readRows {
+		r.err = ErrMixedRowsAndBlocks
+		return false
+	}
+	if r.reading = readBlocks; r.block == nil {
+		return false
+	}
+	if r.row == 0 && r.block.Rows() != 0 {
+		r.row = r.block.Rows()
+		return true
+	}
+	for {
+		select {
+		case err := <-r.errors:
+			if err != nil {
+				r.err = err
+				return false
+			}
+		case block := <-r.stream:
+			switch {
+			case block == nil:
+				return false
+			case r.keep(block):
+				r.row, r.block = 0, nil
+				return false
+			case block.Rows() != 0:
+				r.row, r.block = block.Rows(), block
+				return true
+			}
+		}
+	}
+}
+
+// keep stores the totals and the extremes, they follow the data of the result.
+func (r *rows) keep(block *proto.Block) bool {
+	switch block.Packet {
+	case proto.ServerTotals:
+		r.totals = block
+	case proto.ServerExtremes:
+		r.extremes = block
+	default:
+		return false
+	}
+	return true
+}
+
+// Block returns the block read by NextBlock.
+func (r *rows) Block() driver.Block {
+	if r.reading != readBlocks || r.block == nil {
+		return nil
+	}
+	return &block{
+		block: r.block,
+	}
+}
+
 func (r *rows) Scan(dest ...interface{}) error {
 	if r.block == nil || (r.row == 0 && r.row >= r.block.Rows()) { // call without next when result is empty
 		return io.EOF
@@ -59,11 +143,14 @@ func (r *rows) Scan(dest ...interface{}) error {
 }
 
 func (r *rows) ScanStruct(dest interface{}) error {
-	values, err := structToScannableValues(r.columns, dest)
+	values, nested, err := structToScannableValues(r.columns, dest)
 	if err != nil {
 		return err
 	}
-	return r.Scan(values...)
+	if err := r.Scan(values...); err != nil {
+		return err
+	}
+	return nested.join()
 }
 
 func (r *rows) Totals(dest ...interface{}) error {
@@ -73,18 +160,55 @@ func (r *rows) Totals(dest ...interface{}) error {
 	return scan(r.totals, 1, dest...)
 }
 
+// Extremes scans the minimums (the first row of the extremes) followed by the maximums (the second row)
+// of the columns of a query run with extremes = 1.
+func (r *rows) Extremes(dest ...interface{}) error {
+	if r.extremes == nil {
+		return sql.ErrNoRows
+	}
+	if columns := len(r.extremes.Columns); len(dest) != 2*columns {
+		return &OpError{
+			Op:  "Extremes",
+			Err: fmt.Errorf("expected %d destination arguments (the minimums and the maximums), got %d", 2*columns, len(dest)),
+		}
+	}
+	n := len(dest) / 2
+	if err := scan(r.extremes, 1, dest[:n]...); err != nil {
+		return err
+	}
+	return scan(r.extremes, 2, dest[n:]...)
+}
+
 func (r *rows) Columns() []string {
 	return r.columns
 }
 
+func (r *rows) ColumnTypes() []driver.ColumnType {
+	types := make([]driver.ColumnType, 0, len(r.columns))
+	for i, name := range r.columns {
+		types = append(types, &columnType{
+			name:   name,
+			column: r.types[i],
+		})
+	}
+	return types
+}
+
 func (r *rows) Close() error {
-	for range r.stream {
+	for block := range r.stream {
+		if block != nil {
+			r.keep(block)
+		}
 	}
 	for err := range r.errors {
 		if err != nil {
 			r.err = err
 		}
 	}
+	if r.release != nil {
+		r.release(r.conn)
+		r.release = nil
+	}
 	return nil
 }
 
@@ -92,6 +216,73 @@ func (r *rows) Err() error {
 	return r.err
 }
 
+type block struct {
+	block *proto.Block
+}
+
+func (b *block) Rows() int {
+	return b.block.Rows()
+}
+
+func (b *block) Columns() []string {
+	return b.block.ColumnsNames()
+}
+
+func (b *block) Column(idx int) (interface{}, error) {
+	if idx < 0 || len(b.block.Columns) <= idx {
+		return nil, &OpError{
+			Op:  "block.Column",
+			Err: fmt.Errorf("invalid column index %d", idx),
+		}
+	}
+	return column.Slice(b.block.Columns[idx]), nil
+}
+
+type columnType struct {
+	name   string
+	column column.Interface
+}
+
+func (c *columnType) Name() string {
+	return c.name
+}
+
+func (c *columnType) DatabaseTypeName() string {
+	return string(c.column.Type())
+}
+
+func (c *columnType) ScanType() reflect.Type {
+	return c.column.ScanType()
+}
+
+func (c *columnType) Nullable() bool {
+	return column.IsNullable(c.column)
+}
+
+func (c *columnType) PrecisionScale() (precision, scale int64, ok bool) {
+	return column.PrecisionScale(c.column)
+}
+
+func (c *columnType) Timezone() *time.Location {
+	return column.Timezone(c.column)
+}
+
+func (c *columnType) EnumValues() map[string]int {
+	return column.EnumValues(c.column)
+}
+
+func (c *columnType) Elements() []driver.ColumnType {
+	names, elements := column.Elements(c.column)
+	types := make([]driver.ColumnType, 0, len(elements))
+	for i, element := range elements {
+		types = append(types, &columnType{
+			name:   names[i],
+			column: element,
+		})
+	}
+	return types
+}
+
 type row struct {
 	err  error
 	rows *rows
@@ -102,11 +293,18 @@ func (r *row) Err() error {
 }
 
 func (r *row) ScanStruct(dest interface{}) error {
-	values, err := structToScannableValues(r.rows.columns, dest)
+	if r.err != nil {
+		return r.err
+	}
+	values, nested, err := structToScannableValues(r.rows.columns, dest)
 	if err != nil {
+		r.rows.Close()
 		return err
 	}
-	return r.Scan(values...)
+	if err := r.Scan(values...); err != nil {
+		return err
+	}
+	return nested.join()
 }
 
 func (r *row) Scan(dest ...interface{}) error {
@@ -121,8 +319,7 @@ func (r *row) Scan(dest ...interface{}) error {
 		return sql.ErrNoRows
 	}
 	err := r.rows.Scan(dest...)
-	if err != nil {
-		return err
-	}
-	return r.rows.Close()
+	// the rest of the result is read before the connection goes back to the pool
+	r.rows.Close()
+	return err
 }
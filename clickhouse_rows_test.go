@@ -0,0 +1,176 @@
+package clickhouse
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ClickHouse/clickhouse-go/v2/lib/column"
+	"github.com/ClickHouse/clickhouse-go/v2/lib/proto"
+	"github.com/stretchr/testify/assert"
+)
+
+func testRows(t *testing.T, values ...[]uint64) *rows {
+	packets := make([]byte, len(values))
+	for i := range packets {
+		packets[i] = proto.ServerData
+	}
+	return testResult(t, packets, values...)
+}
+
+func testResult(t *testing.T, packets []byte, values ...[]uint64) *rows {
+	var blocks []*proto.Block
+	for i, values := range values {
+		block := &proto.Block{Packet: packets[i]}
+		if !assert.NoError(t, block.AddColumn("x", "UInt64")) {
+			return nil
+		}
+		for _, v := range values {
+			if !assert.NoError(t, block.Append(v)) {
+				return nil
+			}
+		}
+		blocks = append(blocks, block)
+	}
+	var (
+		errors = make(chan error)
+		stream = make(chan *proto.Block, len(blocks))
+	)
+	for _, block := range blocks[1:] {
+		stream <- block
+	}
+	close(errors)
+	close(stream)
+	return &rows{
+		conn:    &connect{},
+		block:   blocks[0],
+		stream:  stream,
+		errors:  errors,
+		columns: blocks[0].ColumnsNames(),
+	}
+}
+
+func TestRowsNextBlock(t *testing.T) {
+	rows := testRows(t, nil, []uint64{1, 2}, nil, []uint64{3})
+	var values [][]uint64
+	for rows.NextBlock() {
+		block := rows.Block()
+		if assert.Equal(t, []string{"x"}, block.Columns()) {
+			column, err := block.Column(0)
+			if assert.NoError(t, err) {
+				values = append(values, column.([]uint64))
+			}
+			_, err = block.Column(1)
+			assert.Error(t, err)
+		}
+	}
+	if assert.NoError(t, rows.Err()) {
+		assert.Equal(t, [][]uint64{{1, 2}, {3}}, values)
+	}
+}
+
+func TestRowsMixedReads(t *testing.T) {
+	rows := testRows(t, []uint64{1, 2}, []uint64{3})
+	if assert.True(t, rows.Next()) {
+		assert.False(t, rows.NextBlock())
+		assert.Equal(t, ErrMixedRowsAndBlocks, rows.Err())
+	}
+	rows = testRows(t, []uint64{1, 2}, []uint64{3})
+	if assert.True(t, rows.NextBlock()) {
+		assert.False(t, rows.Next())
+		assert.Equal(t, ErrMixedRowsAndBlocks, rows.Err())
+	}
+}
+
+func TestRowsExtremes(t *testing.T) {
+	rows := testResult(t, []byte{proto.ServerData, proto.ServerData, proto.ServerTotals, proto.ServerExtremes},
+		nil, []uint64{1, 2, 3}, []uint64{6}, []uint64{1, 3},
+	)
+	var values []uint64
+	for rows.Next() {
+		var v uint64
+		if assert.NoError(t, rows.Scan(&v)) {
+			values = append(values, v)
+		}
+	}
+	if assert.NoError(t, rows.Err()) && assert.Equal(t, []uint64{1, 2, 3}, values) {
+		var totals, min, max uint64
+		if assert.NoError(t, rows.Totals(&totals)) {
+			assert.Equal(t, uint64(6), totals)
+		}
+		if assert.NoError(t, rows.Extremes(&min, &max)) {
+			assert.Equal(t, uint64(1), min)
+			assert.Equal(t, uint64(3), max)
+		}
+		assert.Error(t, rows.Extremes(&min))
+	}
+}
+
+func TestRowsColumnTypes(t *testing.T) {
+	var block proto.Block
+	for _, c := range []struct{ name, chType string }{
+		{"id", "UInt64"},
+		{"tags", "Array(Nullable(String))"},
+		{"created", "DateTime('UTC')"},
+	} {
+		if !assert.NoError(t, block.AddColumn(c.name, column.Type(c.chType))) {
+			return
+		}
+	}
+	rows := &rows{
+		columns: block.ColumnsNames(),
+		types:   block.Columns,
+	}
+	types := rows.ColumnTypes()
+	if assert.Len(t, types, 3) {
+		assert.Equal(t, "id", types[0].Name())
+		assert.Equal(t, "UInt64", types[0].DatabaseTypeName())
+		assert.Equal(t, reflect.TypeOf(uint64(0)), types[0].ScanType())
+		assert.Empty(t, types[0].Elements())
+		if elements := types[1].Elements(); assert.Len(t, elements, 1) {
+			assert.False(t, types[1].Nullable())
+			assert.True(t, elements[0].Nullable())
+			assert.Equal(t, "Nullable(String)", elements[0].DatabaseTypeName())
+		}
+		if tz := types[2].Timezone(); assert.NotNil(t, tz) {
+			assert.Equal(t, "UTC", tz.String())
+		}
+	}
+}
+
+func TestRowsRelease(t *testing.T) {
+	var released int
+	release := func(*connect) {
+		released++
+	}
+	rows := testRows(t, []uint64{1, 2}, []uint64{3})
+	rows.release = release
+	if assert.True(t, rows.Next()) {
+		// the connection is in use until the result is read
+		assert.Equal(t, 0, released)
+		for rows.Next() {
+		}
+		if assert.NoError(t, rows.Close()) {
+			assert.Equal(t, 1, released)
+		}
+	}
+	// the connection of the row is released after Scan, also when the Scan fails
+	for _, scan := range []func(*row) error{
+		func(r *row) error {
+			var v uint64
+			return r.Scan(&v)
+		},
+		func(r *row) error {
+			var v string
+			return r.Scan(&v)
+		},
+		func(r *row) error {
+			return r.ScanStruct(nil)
+		},
+	} {
+		released = 0
+		r := &row{rows: testRows(t, []uint64{1, 2}, []uint64{3})}
+		r.rows.release = release
+		scan(r)
+		assert.Equal(t, 1, released)
+	}
+}
@@ -0,0 +1,123 @@
+package clickhouse
+
+import (
+	"context"
+	"regexp"
+
+	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
+)
+
+var (
+	// sessionStateRe matches the statements which change the state of the server session,
+	// at the start of the query or after a semicolon.
+	sessionStateRe = regexp.MustCompile(`(?i)(?:^|;)\s*(?:SET|USE|CREATE\s+(?:OR\s+REPLACE\s+)?TEMPORARY)\b`)
+	// sessionLiteralRe matches the comments, the string literals and the quoted identifiers which are blanked
+	// before sessionStateRe, so a statement inside them is not matched and a comment does not hide one.
+	sessionLiteralRe = regexp.MustCompile(`--[^\n]*|#[^\n]*|/\*(?s:.*?)\*/|'(?:[^'\\]|\\.)*'|"(?:[^"\\]|\\.)*"|` + "`(?:[^`\\\\]|\\\\.)*`")
+)
+
+// changesSessionState reports whether one of the statements of the query changes the session state.
+func changesSessionState(query string) bool {
+	return sessionStateRe.MatchString(sessionLiteralRe.ReplaceAllString(query, " "))
+}
+
+type session struct {
+	ch       *clickhouse
+	conn     *connect
+	state    bool
+	released bool
+}
+
+// check fails the calls of a released session and of a session whose connection is broken,
+// e.g. by a cancelled or a failed call which left the protocol state unknown.
+func (s *session) check() error {
+	switch {
+	case s.released, s.conn.closed:
+		return ErrSessionReleased
+	case s.conn.err != nil:
+		return s.conn.err
+	}
+	return nil
+}
+
+// track remembers that the session state has to be dropped before the connection is reused.
+func (s *session) track(query string) error {
+	if err := s.check(); err != nil {
+		return err
+	}
+	if changesSessionState(query) {
+		s.state = true
+	}
+	return nil
+}
+
+func (s *session) Select(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	return selectStructs(dest, func() (driver.Rows, error) {
+		return s.Query(ctx, query, args...)
+	})
+}
+
+func (s *session) Query(ctx context.Context, query string, args ...interface{}) (driver.Rows, error) {
+	if err := s.track(query); err != nil {
+		return nil, err
+	}
+	// the connection stays with the session after the result is read
+	return s.conn.query(ctx, func(*connect) {}, query, args...)
+}
+
+func (s *session) QueryRow(ctx context.Context, query string, args ...interface{}) driver.Row {
+	if err := s.track(query); err != nil {
+		return &row{
+			err: err,
+		}
+	}
+	return s.conn.queryRow(ctx, func(*connect) {}, query, args...)
+}
+
+func (s *session) PrepareBatch(ctx context.Context, query string) (driver.Batch, error) {
+	if err := s.track(query); err != nil {
+		return nil, err
+	}
+	// the connection stays with the session after the batch is sent
+	return s.conn.prepareBatch(ctx, query, func(*connect) {})
+}
+
+func (s *session) Exec(ctx context.Context, query string, args ...interface{}) error {
+	if err := s.track(query); err != nil {
+		return err
+	}
+	return s.conn.exec(ctx, query, args...)
+}
+
+func (s *session) AsyncInsert(ctx context.Context, query string, wait bool, args ...interface{}) error {
+	if err := s.track(query); err != nil {
+		return err
+	}
+	return s.conn.asyncInsert(ctx, query, wait, args...)
+}
+
+func (s *session) Ping(ctx context.Context) error {
+	if err := s.check(); err != nil {
+		return err
+	}
+	return s.conn.ping(ctx)
+}
+
+// Release returns the connection to the pool. The session state is not reset: the server keeps it until
+// the connection is closed and has no statement to reset all of it, so by design the connection of a session
+// which changed it is closed instead and counted in Stats.SessionClosed. A broken connection is closed as an error.
+func (s *session) Release() error {
+	if s.released {
+		return ErrSessionReleased
+	}
+	s.released = true
+	if !s.state || s.conn.err != nil || s.conn.closed {
+		s.ch.release(s.conn)
+		return nil
+	}
+	if s.ch.free(s.conn) {
+		s.conn.debugf("[release] close the connection to drop the session state")
+		s.ch.retire(s.conn, &s.ch.stats.sessionClosed)
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package clickhouse
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSessionState(t *testing.T) {
+	for _, asset := range []struct {
+		query string
+		state bool
+	}{
+		{query: "SET max_threads = 1", state: true},
+		{query: " use db", state: true},
+		{query: "CREATE TEMPORARY TABLE t (x UInt8)", state: true},
+		{query: "create or replace temporary table t (x UInt8)", state: true},
+		{query: "CREATE TABLE t (x UInt8) Engine Memory"},
+		{query: "SELECT * FROM settings"},
+		{query: "INSERT INTO t VALUES"},
+		{query: "-- comment\nSET max_threads = 1", state: true},
+		{query: "/* comment */ USE db", state: true},
+		{query: "SELECT 1; SET max_threads = 1", state: true},
+		{query: "SELECT 1 SETTINGS max_threads = 1"},
+		{query: "SELECT 'a; SET b' FROM t"},
+		{query: "SELECT 1 -- ; SET max_threads = 1"},
+		{query: "SELECT * FROM `t; use db`"},
+	} {
+		s := session{conn: &connect{}}
+		if assert.NoError(t, s.track(asset.query)) {
+			assert.Equal(t, asset.state, s.state, asset.query)
+		}
+	}
+	s := session{released: true}
+	assert.Equal(t, ErrSessionReleased, s.track("SELECT 1"))
+	assert.Equal(t, ErrSessionReleased, s.Release())
+	// the broken connection is not used again
+	s = session{conn: &connect{err: context.Canceled}}
+	assert.Equal(t, context.Canceled, s.track("SELECT 1"))
+	assert.Equal(t, context.Canceled, s.Ping(context.Background()))
+	s = session{conn: &connect{closed: true}}
+	assert.Equal(t, ErrSessionReleased, s.Exec(context.Background(), "SELECT 1"))
+}
+
+func TestSessionRelease(t *testing.T) {
+	opt := &Options{}
+	opt.setDefaults()
+	release := func(query string, err error) (*clickhouse, *connect) {
+		ch := &clickhouse{
+			opt:   opt,
+			idle:  make(chan *connect, 1),
+			open:  make(chan struct{}, 1),
+			stats: &poolStats{},
+			hosts: newHosts(opt),
+		}
+		ch.open <- struct{}{}
+		conn := testIdleConn(time.Now(), time.Time{})
+		conn.debugf = func(string, ...interface{}) {}
+		s := session{ch: ch, conn: conn}
+		if !assert.NoError(t, s.track(query)) {
+			return ch, conn
+		}
+		// the last call of the session broke the connection
+		conn.err = err
+		if assert.NoError(t, s.Release()) {
+			assert.Equal(t, ErrSessionReleased, s.Release())
+		}
+		return ch, conn
+	}
+	// the connection without session state goes back to the idle pool
+	if ch, conn := release("SELECT 1", nil); assert.False(t, conn.closed) {
+		stats := ch.Stats()
+		assert.Equal(t, 1, stats.Idle)
+		assert.Equal(t, 0, stats.InUse)
+		assert.Equal(t, int64(0), stats.SessionClosed)
+	}
+	// the connection with session state is closed, it is not a broken connection
+	if ch, conn := release("SET max_threads = 1", nil); assert.True(t, conn.closed) {
+		stats := ch.Stats()
+		assert.Equal(t, 0, stats.Idle)
+		assert.Equal(t, 0, stats.InUse)
+		assert.Equal(t, int64(1), stats.SessionClosed)
+		assert.Equal(t, int64(0), stats.ErrorClosed)
+	}
+	// the broken connection is closed as an error
+	if ch, conn := release("SET max_threads = 1", context.Canceled); assert.True(t, conn.closed) {
+		stats := ch.Stats()
+		assert.Equal(t, int64(0), stats.SessionClosed)
+		assert.Equal(t, int64(1), stats.ErrorClosed)
+	}
+}
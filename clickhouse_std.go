@@ -52,14 +52,18 @@ func (d *stdDriver) Driver() driver.Driver {
 	return d
 }
 
-func (d *stdDriver) Connect(context.Context) (driver.Conn, error) {
+func (d *stdDriver) Connect(ctx context.Context) (driver.Conn, error) {
 	if d.err != nil {
 		return nil, d.err
 	}
-	return d.Open("")
+	return d.open(ctx, "")
 }
 
 func (d *stdDriver) Open(dsn string) (_ driver.Conn, err error) {
+	return d.open(context.Background(), dsn)
+}
+
+func (d *stdDriver) open(ctx context.Context, dsn string) (_ driver.Conn, err error) {
 	var (
 		conn   *connect
 		connID = int(atomic.AddInt64(&d.connID, 1))
@@ -74,7 +78,7 @@ func (d *stdDriver) Open(dsn string) (_ driver.Conn, err error) {
 		if d.opt.ConnOpenStrategy == ConnOpenRoundRobin {
 			num = int(connID) % len(d.opt.Addr)
 		}
-		if conn, err = dial(d.opt.Addr[num], connID, d.opt); err == nil {
+		if conn, err = dial(ctx, d.opt.Addr[num], connID, d.opt, nil); err == nil {
 			return &stdDriver{
 				conn: conn,
 			}, nil
@@ -120,7 +124,7 @@ func (std *stdDriver) ExecContext(ctx context.Context, query string, args []driv
 }
 
 func (std *stdDriver) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
-	r, err := std.conn.query(ctx, query, rebind(args)...)
+	r, err := std.conn.query(ctx, func(*connect) {}, query, rebind(args)...)
 	if err != nil {
 		return nil, err
 	}
@@ -239,8 +243,9 @@ func (r *stdRows) Next(dest []driver.Value) error {
 	return io.EOF
 }
 
+// HasNextResultSet reports whether the totals or the extremes follow the rows, they are the next result sets in this order.
 func (r *stdRows) HasNextResultSet() bool {
-	return r.rows.totals != nil
+	return r.rows.totals != nil || r.rows.extremes != nil
 }
 
 func (r *stdRows) NextResultSet() error {
@@ -248,9 +253,13 @@ func (r *stdRows) NextResultSet() error {
 	case r.rows.totals != nil:
 		r.rows.block = r.rows.totals
 		r.rows.totals = nil
+	case r.rows.extremes != nil:
+		r.rows.block = r.rows.extremes
+		r.rows.extremes = nil
 	default:
 		return io.EOF
 	}
+	r.rows.row = 0
 	return nil
 }
 
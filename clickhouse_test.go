@@ -0,0 +1,248 @@
+package clickhouse
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
+	"github.com/ClickHouse/clickhouse-go/v2/lib/io"
+	"github.com/ClickHouse/clickhouse-go/v2/lib/proto"
+	"github.com/stretchr/testify/assert"
+)
+
+func testIdleConn(connectedAt, releasedAt time.Time) *connect {
+	client, _ := net.Pipe()
+	return &connect{
+		conn:        client,
+		stream:      io.NewStream(client, 0, 0, 0),
+		connectedAt: connectedAt,
+		releasedAt:  releasedAt,
+	}
+}
+
+func TestPoolRetireExpired(t *testing.T) {
+	opt := &Options{
+		ConnMaxIdleTime: time.Minute,
+	}
+	opt.setDefaults()
+	ch := &clickhouse{
+		opt:   opt,
+		stats: &poolStats{},
+		hosts: newHosts(opt),
+	}
+	now := time.Now()
+	assert.False(t, ch.retireExpired(testIdleConn(now, now)))
+	assert.True(t, ch.retireExpired(testIdleConn(now.Add(-2*time.Hour), now)))
+	assert.True(t, ch.retireExpired(testIdleConn(now, now.Add(-2*time.Minute))))
+	// without ConnMaxIdleTime the idle connections are not closed
+	ch.opt.ConnMaxIdleTime = 0
+	assert.False(t, ch.retireExpired(testIdleConn(now, now.Add(-2*time.Minute))))
+	stats := ch.Stats()
+	assert.Equal(t, int64(1), stats.MaxLifetimeClosed)
+	assert.Equal(t, int64(1), stats.MaxIdleTimeClosed)
+}
+
+func TestPoolCheckIdle(t *testing.T) {
+	conn, err := Open(&Options{
+		ConnMaxIdleTime:     time.Minute,
+		MaintenanceInterval: 10 * time.Millisecond,
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+	ch := conn.(*clickhouse)
+	idle := testIdleConn(time.Now(), time.Now().Add(-time.Hour))
+	ch.idle <- idle
+	assert.Eventually(t, func() bool {
+		return len(ch.idle) == 0
+	}, time.Second, 10*time.Millisecond)
+	// Close waits for the maintenance to stop
+	if assert.NoError(t, conn.Close()) {
+		assert.True(t, idle.closed)
+		assert.Equal(t, int64(1), conn.Stats().MaxIdleTimeClosed)
+	}
+}
+
+func TestPoolWaitStats(t *testing.T) {
+	conn, err := Open(&Options{
+		MaxOpenConns:   1,
+		AcquireTimeout: 50 * time.Millisecond,
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer conn.Close()
+	ch := conn.(*clickhouse)
+	// the only connection is in use
+	ch.open <- struct{}{}
+	_, err = ch.acquire(context.Background())
+	if assert.Equal(t, ErrAcquireConnTimeout, err) {
+		stats := conn.Stats()
+		assert.Equal(t, 1, stats.InUse)
+		assert.Equal(t, int64(1), stats.WaitCount)
+		assert.Equal(t, int64(1), stats.AcquireTimeouts)
+		assert.GreaterOrEqual(t, int64(stats.WaitDuration), int64(50*time.Millisecond))
+	}
+}
+
+func TestCountingConn(t *testing.T) {
+	var (
+		stats          ioStats
+		client, server = net.Pipe()
+		conn           = &countingConn{Conn: client, stats: &stats}
+	)
+	go func() {
+		buf := make([]byte, 3)
+		server.Read(buf)
+		server.Write([]byte("hello"))
+	}()
+	if _, err := conn.Write([]byte("abc")); assert.NoError(t, err) {
+		buf := make([]byte, 5)
+		if _, err := conn.Read(buf); assert.NoError(t, err) {
+			assert.Equal(t, int64(3), stats.sent)
+			assert.Equal(t, int64(5), stats.received)
+		}
+	}
+}
+
+func TestPoolFillIdle(t *testing.T) {
+	var (
+		ch            *clickhouse
+		dialed, inUse int64
+	)
+	conn, err := Open(&Options{
+		Addr:                []string{"host:9000"},
+		MaxOpenConns:        1,
+		MinIdleConns:        1,
+		MaintenanceInterval: time.Hour,
+		DialContext: func(ctx context.Context, addr string) (net.Conn, error) {
+			atomic.AddInt64(&dialed, 1)
+			atomic.StoreInt64(&inUse, int64(len(ch.open)))
+			return nil, errors.New("dial error")
+		},
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer conn.Close()
+	ch = conn.(*clickhouse)
+	// the pool is full, no connection is dialed
+	ch.open <- struct{}{}
+	ch.fillIdle()
+	assert.Equal(t, int64(0), atomic.LoadInt64(&dialed))
+	// the connection is dialed in the free slot, the slot is given back after the dial
+	<-ch.open
+	ch.fillIdle()
+	assert.Equal(t, int64(1), atomic.LoadInt64(&dialed))
+	assert.Equal(t, int64(1), atomic.LoadInt64(&inUse))
+	assert.Equal(t, 0, len(ch.open))
+}
+
+// testCancelDial dials a fake server which ends the handshake and calls cancel while the first query is running.
+func testCancelDial(cancel func(), dialed *[]string) func(context.Context, string) (net.Conn, error) {
+	return func(ctx context.Context, addr string) (net.Conn, error) {
+		*dialed = append(*dialed, addr)
+		client, server := net.Pipe()
+		go func() {
+			defer server.Close()
+			go ioutil.ReadAll(server)
+			// the end of stream ends the handshake
+			if _, err := server.Write([]byte{proto.ServerEndOfStream}); err != nil {
+				return
+			}
+			// the query is cancelled while the server is sending its packets
+			cancel()
+			server.Write([]byte{proto.ServerTimezoneUpdate, 3, 'U', 'T', 'C'})
+		}()
+		return client, nil
+	}
+}
+
+func TestPoolCancel(t *testing.T) {
+	for name, call := range map[string]func(context.Context, driver.Conn) error{
+		"Exec": func(ctx context.Context, conn driver.Conn) error {
+			return conn.Exec(ctx, "SELECT 1")
+		},
+		"Query": func(ctx context.Context, conn driver.Conn) error {
+			_, err := conn.Query(ctx, "SELECT 1")
+			return err
+		},
+		"QueryRow": func(ctx context.Context, conn driver.Conn) error {
+			var v uint8
+			return conn.QueryRow(ctx, "SELECT 1").Scan(&v)
+		},
+		"PrepareBatch": func(ctx context.Context, conn driver.Conn) error {
+			_, err := conn.PrepareBatch(ctx, "INSERT INTO t")
+			return err
+		},
+	} {
+		ctx, cancel := context.WithCancel(context.Background())
+		var dialed []string
+		conn, err := Open(&Options{
+			Addr:                []string{"host:9000"},
+			MaxOpenConns:        1,
+			MaintenanceInterval: time.Hour,
+			DialContext:         testCancelDial(cancel, &dialed),
+		})
+		if !assert.NoError(t, err) {
+			return
+		}
+		if err := call(ctx, conn); assert.Equal(t, context.Canceled, err, name) {
+			// the cancelled connection is closed, it is not counted as open
+			stats := conn.Stats()
+			assert.Equal(t, 0, stats.Open, name)
+			assert.Equal(t, 0, stats.Idle, name)
+			assert.Equal(t, 0, stats.InUse, name)
+			assert.Equal(t, int64(1), stats.ErrorClosed, name)
+		}
+		conn.Close()
+		cancel()
+	}
+}
+
+func TestPoolAcquireContext(t *testing.T) {
+	conn, err := Open(&Options{
+		MaxOpenConns:   1,
+		AcquireTimeout: time.Hour,
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer conn.Close()
+	ch := conn.(*clickhouse)
+	ch.open <- struct{}{}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	// the wait for the connection in use ends with the context
+	if _, err := ch.acquire(ctx); assert.Equal(t, context.DeadlineExceeded, err) {
+		assert.Less(t, int64(time.Since(start)), int64(time.Second))
+		assert.Equal(t, int64(0), conn.Stats().AcquireTimeouts)
+	}
+}
+
+func TestPoolAcquireDialError(t *testing.T) {
+	errDial := errors.New("dial error")
+	conn, err := Open(&Options{
+		Addr:         []string{"host:9000"},
+		MaxOpenConns: 1,
+		DialContext: func(ctx context.Context, addr string) (net.Conn, error) {
+			return nil, errDial
+		},
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer conn.Close()
+	for i := 0; i < 3; i++ {
+		// the failed dial does not keep the only place of the pool
+		_, err := conn.(*clickhouse).acquire(context.Background())
+		assert.Equal(t, errDial, err)
+	}
+	assert.Equal(t, 0, conn.Stats().InUse)
+}
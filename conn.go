@@ -7,37 +7,69 @@ import (
 	"log"
 	"net"
 	"os"
+	"sync/atomic"
 	"time"
 
 	"github.com/ClickHouse/clickhouse-go/v2/lib/binary"
+	"github.com/ClickHouse/clickhouse-go/v2/lib/compress"
 	"github.com/ClickHouse/clickhouse-go/v2/lib/io"
 	"github.com/ClickHouse/clickhouse-go/v2/lib/proto"
 )
 
-func dial(addr string, num int, opt *Options) (*connect, error) {
-	var (
-		err    error
-		conn   net.Conn
-		debugf = func(format string, v ...interface{}) {}
-	)
-	switch {
-	case opt.TLS != nil:
-		conn, err = tls.DialWithDialer(&net.Dialer{Timeout: opt.DialTimeout}, "tcp", addr, opt.TLS)
-	default:
-		conn, err = net.DialTimeout("tcp", addr, opt.DialTimeout)
-	}
+// ioStats count the bytes sent and received by the connections of a pool.
+type ioStats struct {
+	sent     int64
+	received int64
+}
+
+type countingConn struct {
+	net.Conn
+	stats *ioStats
+}
+
+func (c *countingConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	atomic.AddInt64(&c.stats.received, int64(n))
+	return n, err
+}
+
+func (c *countingConn) Write(b []byte) (int, error) {
+	n, err := c.Conn.Write(b)
+	atomic.AddInt64(&c.stats.sent, int64(n))
+	return n, err
+}
+
+func dial(ctx context.Context, addr string, num int, opt *Options, stats *ioStats) (*connect, error) {
+	debugf := func(format string, v ...interface{}) {}
+	conn, err := dialContext(ctx, addr, opt)
 	if err != nil {
 		return nil, err
 	}
 	if opt.Debug {
 		debugf = log.New(os.Stdout, fmt.Sprintf("[clickhouse][conn=%d][%s]", num, conn.RemoteAddr()), 0).Printf
 	}
-	var compression bool
+	var (
+		compression bool
+		method      = compress.NONE
+		level       int
+		blockSize   int
+	)
 	if opt.Compression != nil {
-		compression = opt.Compression.Method == CompressionLZ4
+		switch opt.Compression.Method {
+		case CompressionLZ4, CompressionLZ4HC, CompressionZSTD:
+			compression, method = true, opt.Compression.Method
+			level, blockSize = opt.Compression.Level, opt.Compression.BlockSize
+		}
+	}
+	var rw net.Conn = conn
+	if stats != nil {
+		rw = &countingConn{
+			Conn:  conn,
+			stats: stats,
+		}
 	}
 	var (
-		stream  = io.NewStream(conn)
+		stream  = io.NewStream(rw, method, level, blockSize)
 		connect = &connect{
 			opt:         opt,
 			conn:        conn,
@@ -50,12 +82,57 @@ func dial(addr string, num int, opt *Options) (*connect, error) {
 			connectedAt: time.Now(),
 		}
 	)
-	if err := connect.handshake(opt.Auth.Database, opt.Auth.Username, opt.Auth.Password); err != nil {
+	if err := connect.handshake(ctx, opt.Auth.Database, opt.Auth.Username, opt.Auth.Password); err != nil {
+		conn.Close()
 		return nil, err
 	}
 	return connect, nil
 }
 
+// dialContext opens the socket within DialTimeout, with Options.DialContext when it is set.
+// The TLS handshake is made over the connection returned by Options.DialContext.
+func dialContext(ctx context.Context, addr string, opt *Options) (net.Conn, error) {
+	ctx, cancel := context.WithTimeout(ctx, opt.DialTimeout)
+	defer cancel()
+	switch {
+	case opt.DialContext != nil:
+		conn, err := opt.DialContext(ctx, addr)
+		if err != nil || opt.TLS == nil {
+			return conn, err
+		}
+		return tlsClient(ctx, conn, addr, opt.TLS)
+	case opt.TLS != nil:
+		dialer := tls.Dialer{
+			Config: opt.TLS,
+		}
+		return dialer.DialContext(ctx, "tcp", addr)
+	default:
+		var dialer net.Dialer
+		return dialer.DialContext(ctx, "tcp", addr)
+	}
+}
+
+func tlsClient(ctx context.Context, conn net.Conn, addr string, config *tls.Config) (net.Conn, error) {
+	if config.ServerName == "" {
+		config = config.Clone()
+		if host, _, err := net.SplitHostPort(addr); err == nil {
+			config.ServerName = host
+		} else {
+			config.ServerName = addr
+		}
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	tlsConn := tls.Client(conn, config)
+	if err := tlsConn.Handshake(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	conn.SetDeadline(time.Time{})
+	return tlsConn, nil
+}
+
 // https://github.com/ClickHouse/ClickHouse/blob/master/src/Client/Connection.cpp
 type connect struct {
 	err         error
@@ -71,7 +148,10 @@ type connect struct {
 	revision    uint64
 	compression bool
 	lastUsedIn  time.Time
+	releasedAt  time.Time
 	connectedAt time.Time
+	// onClose is called once the connection is closed
+	onClose func()
 }
 
 func (c *connect) settings(querySettings Settings) []proto.Setting {
@@ -91,6 +171,17 @@ func (c *connect) settings(querySettings Settings) []proto.Setting {
 	return settings
 }
 
+func (c *connect) parameters(queryParameters Parameters) []proto.Parameter {
+	parameters := make([]proto.Parameter, 0, len(queryParameters))
+	for k, v := range queryParameters {
+		parameters = append(parameters, proto.Parameter{
+			Key:   k,
+			Value: v,
+		})
+	}
+	return parameters
+}
+
 func (c *connect) isBad() bool {
 	switch {
 	case c.closed, c.err != nil:
@@ -114,6 +205,9 @@ func (c *connect) close() error {
 		return nil
 	}
 	c.closed = true
+	if c.onClose != nil {
+		c.onClose()
+	}
 	c.encoder = nil
 	c.decoder = nil
 	c.stream.Close()
@@ -149,6 +243,7 @@ func (c *connect) sendData(block *proto.Block, name string) error {
 	if err := c.encoder.String(name); err != nil {
 		return err
 	}
+	defer c.stream.EndPacket()
 	if c.compression {
 		c.stream.Compress(true)
 		defer func() {
@@ -0,0 +1,37 @@
+package clickhouse
+
+import (
+	"context"
+	"time"
+)
+
+func (c *connect) asyncInsert(ctx context.Context, query string, wait bool, args ...interface{}) error {
+	options := queryOptions(ctx)
+	// the named arguments of the {name:Type} placeholders are sent as the query parameters
+	parameters, args, err := bindQueryParameters(c.server.Timezone, query, options.parameters, args...)
+	if err != nil {
+		return err
+	}
+	body, err := bind(c.server.Timezone, query, args...)
+	if err != nil {
+		return err
+	}
+	options.parameters = parameters
+	settings := make(Settings, len(options.settings)+2)
+	for k, v := range options.settings {
+		settings[k] = v
+	}
+	settings["async_insert"] = 1
+	if settings["wait_for_async_insert"] = 0; wait {
+		settings["wait_for_async_insert"] = 1
+	}
+	options.settings = settings
+	if deadline, ok := ctx.Deadline(); ok {
+		c.conn.SetDeadline(deadline)
+		defer c.conn.SetDeadline(time.Time{})
+	}
+	if c.err = c.sendQuery(body, &options); c.err != nil {
+		return c.err
+	}
+	return c.process(ctx, options.onProcess())
+}
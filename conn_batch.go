@@ -2,12 +2,14 @@ package clickhouse
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math/big"
+	"reflect"
 	"regexp"
 	"strings"
 	"time"
 
-	"github.com/ClickHouse/clickhouse-go/v2/lib/column"
 	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
 	"github.com/ClickHouse/clickhouse-go/v2/lib/proto"
 )
@@ -29,23 +31,35 @@ func (c *connect) prepareBatch(ctx context.Context, query string, release func(*
 		return nil, c.err
 	}
 	var (
-		onProcess  = options.onProcess()
-		block, err = c.firstBlock(ctx, onProcess)
+		columns   []driver.ColumnDescription
+		onProcess = options.onProcess()
 	)
+	onProcess.tableColumns = func(t *proto.TableColumns) {
+		columns = t.Columns
+	}
+	block, err := c.firstBlock(ctx, onProcess)
 	if err != nil {
 		release(c)
 		return nil, err
 	}
+	return newBatch(ctx, c, query, block, columns, &options, release), nil
+}
+
+func newBatch(ctx context.Context, c *connect, query string, block *proto.Block, columns []driver.ColumnDescription, options *QueryOptions, release func(*connect)) *batch {
 	return &batch{
-		ctx:   ctx,
-		conn:  c,
-		block: block,
+		ctx:       ctx,
+		conn:      c,
+		query:     query,
+		block:     block,
+		columns:   columns,
+		autoFlush: options.autoFlush,
 		release: func(err error) {
 			c.err = err
 			release(c)
 		},
-		onProcess: onProcess,
-	}, nil
+		releaseConn: release,
+		onProcess:   options.onProcess(),
+	}
 }
 
 type batch struct {
@@ -53,9 +67,28 @@ type batch struct {
 	ctx       context.Context
 	conn      *connect
 	sent      bool
+	flushed   bool
+	query     string
 	block     *proto.Block
+	columns   []driver.ColumnDescription
 	release   func(error)
 	onProcess *onProcess
+	// acquire and releaseConn take the connections of the cloned batches from the pool, acquire is nil for the batches of database/sql
+	acquire     func(context.Context) (*connect, error)
+	releaseConn func(*connect)
+	// pending is set until the header block of the INSERT sent by a cloned batch is read
+	pending bool
+	// size is the approximate size of the rows appended since the last flush
+	size      int
+	autoFlush struct {
+		rows  int
+		bytes int
+	}
+}
+
+// TableColumns returns the description of the columns of the table sent by the server, nil if it was not sent.
+func (b *batch) TableColumns() []driver.ColumnDescription {
+	return b.columns
 }
 
 func (b *batch) Append(v ...interface{}) error {
@@ -63,20 +96,167 @@ func (b *batch) Append(v ...interface{}) error {
 		return ErrBatchAlreadySent
 	}
 	if err := b.block.Append(v...); err != nil {
+		b.err = err
 		b.release(err)
 		return err
 	}
+	if b.autoFlush.bytes > 0 {
+		for _, v := range v {
+			b.size += valueSize(reflect.ValueOf(v))
+		}
+	}
+	return b.flushIfFull()
+}
+
+// Flush sends the appended rows to the server and resets the columns, the INSERT stays open until Send.
+func (b *batch) Flush() (err error) {
+	if b.sent {
+		return ErrBatchAlreadySent
+	}
+	if b.err != nil {
+		return b.err
+	}
+	if b.block.Rows() == 0 {
+		return nil
+	}
+	defer func() {
+		if err != nil {
+			b.err = err
+			b.release(err)
+		}
+	}()
+	if err = b.readHeader(); err != nil {
+		return err
+	}
+	if err = b.conn.sendData(b.block, ""); err != nil {
+		return err
+	}
+	if err = b.conn.encoder.Flush(); err != nil {
+		return err
+	}
+	b.size, b.flushed = 0, true
+	return b.block.Reset()
+}
+
+func (b *batch) flushIfFull() error {
+	switch {
+	case b.autoFlush.rows > 0 && b.block.Rows() >= b.autoFlush.rows,
+		b.autoFlush.bytes > 0 && b.size >= b.autoFlush.bytes:
+		return b.Flush()
+	}
 	return nil
 }
 
+// AppendStruct appends the fields of the struct to the columns of the same name. When the first struct of the
+// batch has no field for some columns with a DEFAULT or EPHEMERAL expression, the INSERT is sent again without
+// these columns which costs an extra round trip to the server per batch. Without the description of the
+// columns (old servers or a description which could not be parsed) all the columns are appended.
 func (b *batch) AppendStruct(v interface{}) error {
-	values, err := structToScannableValues(b.block.ColumnsNames(), v)
+	if b.sent {
+		return ErrBatchAlreadySent
+	}
+	if b.block.Rows() == 0 && !b.flushed {
+		if omit := b.omittedColumns(v); len(omit) != 0 {
+			if err := b.insertColumns(omit); err != nil {
+				b.err = err
+				b.release(err)
+				return err
+			}
+		}
+	}
+	values, nested, err := structToScannableValues(b.block.ColumnsNames(), v)
 	if err != nil {
 		return err
 	}
+	nested.split()
 	return b.Append(values...)
 }
 
+// omittedColumns returns the columns with a server side default which have no field in the struct.
+func (b *batch) omittedColumns(v interface{}) []string {
+	var omit []string
+	for _, c := range b.columns {
+		if !c.HasDefault() || !b.hasColumn(c.Name) {
+			continue
+		}
+		if _, _, err := structToScannableValues([]string{c.Name}, v); err != nil {
+			omit = append(omit, c.Name)
+		}
+	}
+	return omit
+}
+
+func (b *batch) hasColumn(name string) bool {
+	for _, c := range b.block.ColumnsNames() {
+		if c == name {
+			return true
+		}
+	}
+	return false
+}
+
+// insertColumns finishes the empty INSERT and sends it again with the explicit list of the inserted columns,
+// the server computes the values of the omitted ones. It is a round trip to the server before the first block.
+func (b *batch) insertColumns(omit []string) error {
+	columns := make([]string, 0, len(b.block.Columns))
+	for _, name := range b.block.ColumnsNames() {
+		omitted := false
+		for _, o := range omit {
+			omitted = omitted || o == name
+		}
+		if !omitted {
+			columns = append(columns, name)
+		}
+	}
+	query, ok := insertQueryColumns(b.query, columns)
+	if !ok {
+		return &OpError{
+			Op:  "AppendStruct",
+			Err: fmt.Errorf("can not omit the columns %v of the query %q", omit, b.query),
+		}
+	}
+	if err := b.readHeader(); err != nil {
+		return err
+	}
+	if err := b.conn.sendData(&proto.Block{}, ""); err != nil {
+		return err
+	}
+	if err := b.conn.encoder.Flush(); err != nil {
+		return err
+	}
+	if err := b.conn.process(b.ctx, b.onProcess); err != nil {
+		return err
+	}
+	options := queryOptions(b.ctx)
+	if err := b.conn.sendQuery(query, &options); err != nil {
+		return err
+	}
+	block, err := b.conn.firstBlock(b.ctx, b.onProcess)
+	if err != nil {
+		return err
+	}
+	b.query, b.block = query, block
+	return nil
+}
+
+// insertTableIdentifier is a bare, a backquoted or a double quoted identifier.
+const insertTableIdentifier = "(?:`(?:[^`\\\\]|\\\\.)*`|\"(?:[^\"\\\\]|\\\\.)*\"|[^\\s(.`\"]+)"
+
+var insertColumnsRe = regexp.MustCompile(`(?is)^(\s*INSERT\s+INTO\s+(?:TABLE\s+)?(` + insertTableIdentifier + `(?:\.` + insertTableIdentifier + `)?))\s*(?:\([^)]*\))?(.*)$`)
+
+// insertQueryColumns replaces the list of the columns of the INSERT query.
+func insertQueryColumns(query string, columns []string) (string, bool) {
+	match := insertColumnsRe.FindStringSubmatch(query)
+	if match == nil || strings.EqualFold(match[2], "FUNCTION") {
+		return "", false
+	}
+	quoted := make([]string, 0, len(columns))
+	for _, c := range columns {
+		quoted = append(quoted, "`"+strings.NewReplacer(`\`, `\\`, "`", "\\`").Replace(c)+"`")
+	}
+	return fmt.Sprintf("%s (%s) %s", match[1], strings.Join(quoted, ", "), strings.TrimSpace(match[3])), true
+}
+
 func (b *batch) Column(idx int) driver.BatchColumn {
 	if len(b.block.Columns) <= idx {
 		b.release(nil)
@@ -88,8 +268,8 @@ func (b *batch) Column(idx int) driver.BatchColumn {
 		}
 	}
 	return &batchColumn{
-		batch:  b,
-		column: b.block.Columns[idx],
+		batch: b,
+		idx:   idx,
 		release: func(err error) {
 			b.err = err
 			b.release(err)
@@ -108,9 +288,14 @@ func (b *batch) Send() (err error) {
 	if b.err != nil {
 		return b.err
 	}
-	if err = b.conn.sendData(b.block, ""); err != nil {
+	if err = b.readHeader(); err != nil {
 		return err
 	}
+	if b.block.Rows() != 0 || !b.flushed {
+		if err = b.conn.sendData(b.block, ""); err != nil {
+			return err
+		}
+	}
 	if err = b.conn.sendData(&proto.Block{}, ""); err != nil {
 		return err
 	}
@@ -123,10 +308,116 @@ func (b *batch) Send() (err error) {
 	return nil
 }
 
+// Abort cancels the INSERT and returns the connection to the pool, the rows sent by Flush may be already inserted.
+func (b *batch) Abort() (err error) {
+	if b.sent {
+		return ErrBatchAlreadySent
+	}
+	b.sent = true
+	if b.err != nil {
+		// the connection has already been released
+		return nil
+	}
+	defer func() {
+		b.release(err)
+	}()
+	// as for a cancelled context, a server which does not finish the query in time costs the connection
+	b.conn.conn.SetDeadline(time.Now().Add(cancelTimeout))
+	defer b.conn.conn.SetDeadline(time.Time{})
+	if err = b.conn.sendCancel(); err != nil {
+		return err
+	}
+	// the server finishes the query with the end of stream or the exception of the cancelled query
+	switch err = b.conn.process(b.ctx, b.onProcess); e := err.(type) {
+	case *Exception:
+		if e.Code == errCodeQueryWasCancelled {
+			return nil
+		}
+	}
+	return err
+}
+
+// Reset drops the rows which have not been sent to the server. A batch which has been sent or aborted
+// starts a new INSERT with the same columns on a connection from the pool, as Clone does.
+func (b *batch) Reset() error {
+	if !b.sent && b.err == nil {
+		b.size = 0
+		return b.block.Reset()
+	}
+	batch, err := b.clone()
+	if err != nil {
+		return err
+	}
+	*b = *batch
+	return nil
+}
+
+// Clone starts a new INSERT with the same query and columns on a connection from the pool. It does not
+// wait for the server to describe the columns, the structure of the table is checked when the rows are sent.
+func (b *batch) Clone() (driver.Batch, error) {
+	return b.clone()
+}
+
+func (b *batch) clone() (*batch, error) {
+	if b.acquire == nil {
+		return nil, &OpError{
+			Op:  "batch.Clone",
+			Err: errors.New("the batch is not bound to a connection pool"),
+		}
+	}
+	block := &proto.Block{}
+	for i, name := range b.block.ColumnsNames() {
+		if err := block.AddColumn(name, b.block.Columns[i].Type()); err != nil {
+			return nil, err
+		}
+	}
+	conn, err := b.acquire(b.ctx)
+	if err != nil {
+		return nil, err
+	}
+	options := queryOptions(b.ctx)
+	if conn.err = conn.sendQuery(b.query, &options); conn.err != nil {
+		b.releaseConn(conn)
+		return nil, conn.err
+	}
+	batch := newBatch(b.ctx, conn, b.query, block, b.columns, &options, b.releaseConn)
+	batch.acquire, batch.pending = b.acquire, true
+	return batch, nil
+}
+
+// readHeader reads the header block of the INSERT of a cloned batch and checks that the columns have not changed.
+func (b *batch) readHeader() error {
+	if !b.pending {
+		return nil
+	}
+	header, err := b.conn.firstBlock(b.ctx, b.onProcess)
+	if err != nil {
+		return err
+	}
+	b.pending = false
+	names := b.block.ColumnsNames()
+	if len(header.Columns) != len(names) {
+		return &OpError{
+			Op:  "batch.Clone",
+			Err: fmt.Errorf("expected %d columns, the server sent %d", len(names), len(header.Columns)),
+		}
+	}
+	for i, name := range header.ColumnsNames() {
+		if name != names[i] || header.Columns[i].Type() != b.block.Columns[i].Type() {
+			return &OpError{
+				Op:         "batch.Clone",
+				ColumnName: names[i],
+				Err:        fmt.Errorf("the server sent the column %s %s instead of %s", name, header.Columns[i].Type(), b.block.Columns[i].Type()),
+			}
+		}
+	}
+	return nil
+}
+
 type batchColumn struct {
 	err     error
+	idx     int
 	batch   *batch
-	column  column.Interface
 	release func(error)
 }
 
@@ -138,13 +429,57 @@ func (b *batchColumn) Append(v interface{}) (err error) {
 		b.release(b.err)
 		return b.err
 	}
-	if _, err = b.column.Append(v); err != nil {
+	// the columns of the block are replaced when the batch is flushed
+	if _, err = b.batch.block.Columns[b.idx].Append(v); err != nil {
 		b.release(err)
 		return err
 	}
 	return nil
 }
 
+// valueSize returns the approximate size of the value in the native format.
+func valueSize(v reflect.Value) int {
+	switch v.Kind() {
+	case reflect.Invalid:
+		return 1
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return 1
+		}
+		return valueSize(v.Elem())
+	case reflect.String:
+		return v.Len() + 1
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return v.Len() + 1
+		}
+		size := 8
+		for i := 0; i < v.Len(); i++ {
+			size += valueSize(v.Index(i))
+		}
+		return size
+	case reflect.Map:
+		size, iter := 8, v.MapRange()
+		for iter.Next() {
+			size += valueSize(iter.Key()) + valueSize(iter.Value())
+		}
+		return size
+	case reflect.Struct:
+		switch v.Type() {
+		case reflect.TypeOf(time.Time{}):
+			return 8
+		case reflect.TypeOf(big.Int{}):
+			return 32
+		}
+		var size int
+		for i := 0; i < v.NumField(); i++ {
+			size += valueSize(v.Field(i))
+		}
+		return size
+	}
+	return int(v.Type().Size())
+}
+
 var (
 	_ (driver.Batch)       = (*batch)(nil)
 	_ (driver.BatchColumn) = (*batchColumn)(nil)
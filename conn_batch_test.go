@@ -0,0 +1,139 @@
+package clickhouse
+
+import (
+	"context"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ClickHouse/clickhouse-go/v2/lib/binary"
+	"github.com/ClickHouse/clickhouse-go/v2/lib/compress"
+	"github.com/ClickHouse/clickhouse-go/v2/lib/io"
+	"github.com/ClickHouse/clickhouse-go/v2/lib/proto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInsertQueryColumns(t *testing.T) {
+	for _, asset := range []struct {
+		query    string
+		expected string
+		ok       bool
+	}{
+		{query: "INSERT INTO t VALUES", expected: "INSERT INTO t (`a`, `b\\`c`) VALUES", ok: true},
+		{query: "insert into db.t (a, b, c) VALUES", expected: "insert into db.t (`a`, `b\\`c`) VALUES", ok: true},
+		{query: "INSERT INTO TABLE t(a) SETTINGS async_insert = 1 VALUES", expected: "INSERT INTO TABLE t (`a`, `b\\`c`) SETTINGS async_insert = 1 VALUES", ok: true},
+		{query: "INSERT INTO `my db`.`my (table)` VALUES", expected: "INSERT INTO `my db`.`my (table)` (`a`, `b\\`c`) VALUES", ok: true},
+		{query: "INSERT INTO `t\\`x` (a) SETTINGS async_insert = 1 VALUES", expected: "INSERT INTO `t\\`x` (`a`, `b\\`c`) SETTINGS async_insert = 1 VALUES", ok: true},
+		{query: `INSERT INTO "db"."my table" SETTINGS max_insert_block_size = 10 FORMAT Native`, expected: "INSERT INTO \"db\".\"my table\" (`a`, `b\\`c`) SETTINGS max_insert_block_size = 10 FORMAT Native", ok: true},
+		{query: "INSERT INTO FUNCTION remote('127.0.0.1', db.t) VALUES"},
+		{query: "SELECT 1"},
+	} {
+		query, ok := insertQueryColumns(asset.query, []string{"a", "b`c"})
+		if assert.Equal(t, asset.ok, ok, asset.query) && ok {
+			assert.Equal(t, asset.expected, query)
+		}
+	}
+}
+
+func TestValueSize(t *testing.T) {
+	str := "abc"
+	for _, asset := range []struct {
+		value    interface{}
+		expected int
+	}{
+		{value: nil, expected: 1},
+		{value: uint32(42), expected: 4},
+		{value: "abc", expected: 4},
+		{value: &str, expected: 4},
+		{value: []byte{1, 2}, expected: 3},
+		{value: []string{"a", "bc"}, expected: 8 + 2 + 3},
+		{value: map[string]uint64{"a": 1}, expected: 8 + 2 + 8},
+		{value: time.Now(), expected: 8},
+		{value: struct {
+			A uint8
+			b string
+		}{A: 1, b: "abc"}, expected: 1 + 4},
+	} {
+		assert.Equal(t, asset.expected, valueSize(reflect.ValueOf(asset.value)), "%#v", asset.value)
+	}
+}
+
+func TestBatchReset(t *testing.T) {
+	b := batch{block: &proto.Block{}}
+	if assert.NoError(t, b.block.AddColumn("id", "UInt64")) && assert.NoError(t, b.Append(uint64(1))) {
+		if assert.NoError(t, b.Reset()) {
+			assert.Equal(t, 0, b.block.Rows())
+		}
+	}
+	b.sent = true
+	// the batches of database/sql can not start a new INSERT
+	assert.Error(t, b.Reset())
+	_, err := b.Clone()
+	assert.Error(t, err)
+}
+
+func TestBatchAbort(t *testing.T) {
+	exception := func(code int32) func(*binary.Encoder) {
+		return func(encoder *binary.Encoder) {
+			encoder.Byte(proto.ServerException)
+			encoder.Int32(code)
+			encoder.String("DB::Exception")
+			encoder.String("the exception")
+			encoder.String("")
+			encoder.Bool(false)
+		}
+	}
+	abort := func(reply func(*binary.Encoder)) (error, error) {
+		client, server := net.Pipe()
+		defer client.Close()
+		defer server.Close()
+		var (
+			released error
+			stream   = io.NewStream(client, compress.NONE, 0, 0)
+			b        = &batch{
+				ctx: context.Background(),
+				conn: &connect{
+					opt:     &Options{},
+					conn:    client,
+					debugf:  func(string, ...interface{}) {},
+					stream:  stream,
+					encoder: binary.NewEncoder(stream),
+					decoder: binary.NewDecoder(stream),
+				},
+				onProcess: &onProcess{},
+				release: func(err error) {
+					released = err
+				},
+			}
+		)
+		go func() {
+			// the cancel packet
+			server.Read(make([]byte, 1))
+			if reply != nil {
+				reply(binary.NewEncoder(server))
+			}
+		}()
+		return b.Abort(), released
+	}
+	// the exception of the cancelled query
+	if err, released := abort(exception(errCodeQueryWasCancelled)); assert.NoError(t, err) {
+		assert.NoError(t, released)
+	}
+	if err, released := abort(func(encoder *binary.Encoder) { encoder.Byte(proto.ServerEndOfStream) }); assert.NoError(t, err) {
+		assert.NoError(t, released)
+	}
+	// another exception is not hidden
+	if err, released := abort(exception(241)); assert.Error(t, err) {
+		if exception, ok := err.(*Exception); assert.True(t, ok) {
+			assert.Equal(t, int32(241), exception.Code)
+		}
+		assert.Equal(t, err, released)
+	}
+	// a server which does not reply costs the connection after cancelTimeout
+	start := time.Now()
+	if err, released := abort(nil); assert.Error(t, err) {
+		assert.Equal(t, err, released)
+		assert.Less(t, int64(time.Since(start)), int64(cancelTimeout+time.Second))
+	}
+}
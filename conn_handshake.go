@@ -1,16 +1,43 @@
 package clickhouse
 
 import (
+	"context"
 	"fmt"
 	"time"
 
 	"github.com/ClickHouse/clickhouse-go/v2/lib/proto"
 )
 
-func (c *connect) handshake(database, username, password string) error {
+func (c *connect) handshake(ctx context.Context, database, username, password string) (err error) {
 	c.debugf("[handshake] -> %s", proto.ClientHandshake{})
-	c.conn.SetDeadline(time.Now().Add(c.opt.DialTimeout))
+	deadline := time.Now().Add(c.opt.DialTimeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	c.conn.SetDeadline(deadline)
 	defer c.conn.SetDeadline(time.Time{})
+	if ctx.Done() != nil {
+		var (
+			stop = make(chan struct{})
+			done = make(chan struct{})
+		)
+		// a cancelled context interrupts the reads and the writes of the handshake
+		go func() {
+			defer close(done)
+			select {
+			case <-ctx.Done():
+				c.conn.SetDeadline(time.Now())
+			case <-stop:
+			}
+		}()
+		defer func() {
+			close(stop)
+			<-done
+			if err != nil && ctx.Err() != nil {
+				err = ctx.Err()
+			}
+		}()
+	}
 	{
 		c.encoder.Byte(proto.ClientHello)
 		if err := (&proto.ClientHandshake{}).Encode(c.encoder); err != nil {
@@ -58,5 +85,32 @@ func (c *connect) handshake(database, username, password string) error {
 		c.debugf("[handshake] downgrade client proto")
 	}
 	c.debugf("[handshake] <- %s", c.server)
+	if c.revision >= proto.DBMS_MIN_PROTOCOL_VERSION_WITH_ADDENDUM {
+		return c.sendAddendum()
+	}
+	return nil
+}
+
+func (c *connect) sendAddendum() (err error) {
+	addendum := proto.ClientAddendum{
+		QuotaKey: c.opt.Auth.QuotaKey,
+	}
+	if c.revision >= proto.DBMS_MIN_PROTOCOL_VERSION_WITH_CHUNKED_PACKETS {
+		// the client follows the server, the packets are chunked only if the server requires it
+		if addendum.ChunkedSend, err = proto.NegotiateChunked(c.server.ChunkedRecv, proto.NotChunkedOptional); err != nil {
+			return err
+		}
+		if addendum.ChunkedRecv, err = proto.NegotiateChunked(c.server.ChunkedSend, proto.NotChunkedOptional); err != nil {
+			return err
+		}
+	}
+	c.debugf("[handshake] -> addendum chunked send=%t recv=%t", addendum.ChunkedSend, addendum.ChunkedRecv)
+	if err := addendum.Encode(c.encoder, c.revision); err != nil {
+		return err
+	}
+	if err := c.encoder.Flush(); err != nil {
+		return err
+	}
+	c.stream.Chunked(addendum.ChunkedSend, addendum.ChunkedRecv)
 	return nil
 }
@@ -20,6 +20,9 @@ func (c *connect) ping(ctx context.Context) error {
 	if c.err = c.encoder.Byte(proto.ClientPing); c.err != nil {
 		return c.err
 	}
+	if c.err = c.stream.EndPacket(); c.err != nil {
+		return c.err
+	}
 	if c.err = c.encoder.Flush(); c.err != nil {
 		return c.err
 	}
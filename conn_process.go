@@ -15,29 +15,34 @@ type onProcess struct {
 	progress      func(*Progress)
 	profileInfo   func(*ProfileInfo)
 	profileEvents func([]ProfileEvent)
+	tableColumns  func(*proto.TableColumns)
 }
 
 func (c *connect) firstBlock(ctx context.Context, on *onProcess) (*proto.Block, error) {
 	for {
 		select {
 		case <-ctx.Done():
-			c.cancel()
+			c.cancel(ctx.Err())
 			return nil, ctx.Err()
 		default:
 		}
 		packet, err := c.decoder.ReadByte()
 		if err != nil {
-			return nil, err
+			return nil, c.broken(err)
 		}
 		switch packet {
 		case proto.ServerData:
-			return c.readData(packet, true)
+			block, err := c.readData(packet, true)
+			if err != nil {
+				return nil, c.broken(err)
+			}
+			return block, nil
 		case proto.ServerEndOfStream:
 			c.debugf("[end of stream]")
 			return nil, io.EOF
 		default:
 			if err := c.handle(packet, on); err != nil {
-				return nil, err
+				return nil, c.broken(err)
 			}
 		}
 	}
@@ -48,13 +53,13 @@ func (c *connect) process(ctx context.Context, on *onProcess) error {
 	for {
 		select {
 		case <-ctx.Done():
-			c.cancel()
+			c.cancel(ctx.Err())
 			return ctx.Err()
 		default:
 		}
 		packet, err := c.decoder.ReadByte()
 		if err != nil {
-			return err
+			return c.broken(err)
 		}
 		switch packet {
 		case proto.ServerEndOfStream:
@@ -62,11 +67,20 @@ func (c *connect) process(ctx context.Context, on *onProcess) error {
 			return nil
 		}
 		if err := c.handle(packet, on); err != nil {
-			return err
+			return c.broken(err)
 		}
 	}
 }
 
+// broken keeps the error of the connection whose protocol state is lost, so it is not used again.
+// The connection stays usable after an exception of the server, the query ends with it.
+func (c *connect) broken(err error) error {
+	if _, ok := err.(*Exception); !ok {
+		c.err = err
+	}
+	return err
+}
+
 func (c *connect) handle(packet byte, on *onProcess) error {
 	switch packet {
 	case proto.ServerData, proto.ServerTotals, proto.ServerExtremes:
@@ -91,7 +105,14 @@ func (c *connect) handle(packet byte, on *onProcess) error {
 		if err := info.Decode(c.decoder, c.revision); err != nil {
 			return err
 		}
-		c.debugf("[table columns]")
+		// the description only lets AppendStruct omit the columns with defaults, the INSERT goes on without it
+		if err := info.ParseColumns(); err != nil {
+			c.debugf("[table columns] %v", err)
+		}
+		c.debugf("[table columns] %d columns", len(info.Columns))
+		if on.tableColumns != nil {
+			on.tableColumns(&info)
+		}
 	case proto.ServerProfileEvents:
 		events, err := c.profileEvents()
 		if err != nil {
@@ -104,6 +125,33 @@ func (c *connect) handle(packet byte, on *onProcess) error {
 			return err
 		}
 		on.logs(logs)
+	case proto.ServerTablesStatus:
+		var status proto.TablesStatusResponse
+		if err := status.Decode(c.decoder, c.revision); err != nil {
+			return err
+		}
+		c.debugf("[tables status] %v", status.Tables)
+	case proto.ServerPartUUIDs:
+		var uuids proto.PartUUIDs
+		if err := uuids.Decode(c.decoder); err != nil {
+			return err
+		}
+		c.debugf("[part uuids] %d parts", len(uuids))
+	case proto.ServerReadTaskRequest:
+		c.debugf("[read task request]")
+		return c.sendReadTaskResponse()
+	case proto.ServerTreeReadTaskRequest, proto.ServerMergeTreeReadTask:
+		// the packets of the parallel replicas coordination are sent by the replicas to the initiator server only
+		return &OpError{
+			Op:  "process",
+			Err: fmt.Errorf("unexpected packet %d, the client can not coordinate the parallel replicas reading", packet),
+		}
+	case proto.ServerTimezoneUpdate:
+		name, err := c.decoder.String()
+		if err != nil {
+			return err
+		}
+		c.debugf("[timezone update] %s", name)
 	case proto.ServerProgress:
 		progress, err := c.progress()
 		if err != nil {
@@ -120,11 +168,41 @@ func (c *connect) handle(packet byte, on *onProcess) error {
 	return nil
 }
 
-func (c *connect) cancel() error {
-	c.conn.SetDeadline(time.Now().Add(2 * time.Second))
+// sendReadTaskResponse answers that there are no tasks, the client does not distribute the reading.
+func (c *connect) sendReadTaskResponse() error {
+	if err := c.encoder.Byte(proto.ClientReadTaskResponse); err != nil {
+		return err
+	}
+	if err := (&proto.ReadTaskResponse{}).Encode(c.encoder); err != nil {
+		return err
+	}
+	if err := c.stream.EndPacket(); err != nil {
+		return err
+	}
+	return c.encoder.Flush()
+}
+
+// cancelTimeout bounds the wait for the server to finish a cancelled query.
+const cancelTimeout = 2 * time.Second
+
+// errCodeQueryWasCancelled is the code of the exception of the queries cancelled by the client.
+const errCodeQueryWasCancelled = 394
+
+// cancel asks the server to stop the query of the cancelled context. The rest of the query is not read,
+// so the connection keeps the error and is closed by the pool instead of being reused.
+func (c *connect) cancel(err error) error {
+	c.conn.SetDeadline(time.Now().Add(cancelTimeout))
+	c.err = err
+	return c.sendCancel()
+}
+
+// sendCancel asks the server to stop the query, the connection can be used again once the query is finished.
+func (c *connect) sendCancel() error {
 	c.debugf("[cancel]")
-	c.closed = true
-	if err := c.encoder.Uvarint(proto.ClientCancel); err == nil {
+	if err := c.encoder.Uvarint(proto.ClientCancel); err != nil {
+		return err
+	}
+	if err := c.stream.EndPacket(); err != nil {
 		return err
 	}
 	return c.encoder.Flush()
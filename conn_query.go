@@ -7,14 +7,16 @@ import (
 	"github.com/ClickHouse/clickhouse-go/v2/lib/proto"
 )
 
-func (c *connect) query(ctx context.Context, query string, args ...interface{}) (*rows, error) {
+// query sends the query and streams its result, release is called once the result is read or on the error.
+func (c *connect) query(ctx context.Context, release func(*connect), query string, args ...interface{}) (*rows, error) {
 	var (
 		options   = queryOptions(ctx)
 		onProcess = options.onProcess()
-		body, err = bind(c.server.Timezone, query, args...)
+		body, err = c.bindQuery(query, &options, args...)
 	)
 
 	if err != nil {
+		release(c)
 		return nil, err
 	}
 
@@ -24,12 +26,14 @@ func (c *connect) query(ctx context.Context, query string, args ...interface{})
 	}
 
 	if c.err = c.sendQuery(body, &options); c.err != nil {
+		release(c)
 		return nil, c.err
 	}
 
 	init, err := c.firstBlock(ctx, onProcess)
 
 	if err != nil {
+		release(c)
 		return nil, err
 	}
 
@@ -55,12 +59,14 @@ func (c *connect) query(ctx context.Context, query string, args ...interface{})
 		block:   init,
 		stream:  stream,
 		errors:  errors,
+		release: release,
 		columns: init.ColumnsNames(),
+		types:   init.Columns,
 	}, nil
 }
 
-func (c *connect) queryRow(ctx context.Context, query string, args ...interface{}) *row {
-	rows, err := c.query(ctx, query, args...)
+func (c *connect) queryRow(ctx context.Context, release func(*connect), query string, args ...interface{}) *row {
+	rows, err := c.query(ctx, release, query, args...)
 	if err != nil {
 		return &row{
 			err: err,
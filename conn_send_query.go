@@ -6,7 +6,17 @@ import (
 
 // Connection::sendQuery
 // https://github.com/ClickHouse/ClickHouse/blob/master/src/Client/Connection.cpp
-func (c *connect) sendQuery(body string, o *QueryOptions) error {
+func (c *connect) sendQuery(body string, o *QueryOptions) (err error) {
+	var parameters proto.Parameters
+	if len(o.parameters) != 0 {
+		if c.revision < proto.DBMS_MIN_PROTOCOL_VERSION_WITH_PARAMETERS {
+			if body, err = bindParameters(body, o.parameters); err != nil {
+				return err
+			}
+		} else {
+			parameters = c.parameters(o.parameters)
+		}
+	}
 	c.debugf("[send query] compression=%t %s", c.compression, body)
 	if err := c.encoder.Byte(proto.ClientQuery); err != nil {
 		return err
@@ -19,10 +29,14 @@ func (c *connect) sendQuery(body string, o *QueryOptions) error {
 		Compression:    c.compression,
 		InitialAddress: c.conn.LocalAddr().String(),
 		Settings:       c.settings(o.settings),
+		Parameters:     parameters,
 	}
 	if err := q.Encode(c.encoder, c.revision); err != nil {
 		return err
 	}
+	if err := c.stream.EndPacket(); err != nil {
+		return err
+	}
 	for _, table := range o.external {
 		if err := c.sendData(table.Block(), table.Name()); err != nil {
 			return err
@@ -33,3 +47,16 @@ func (c *connect) sendQuery(body string, o *QueryOptions) error {
 	}
 	return c.encoder.Flush()
 }
+
+// bindQuery binds the arguments into the query. The named arguments of the {name:Type} placeholders
+// are sent as the query parameters to the servers which support them.
+func (c *connect) bindQuery(query string, o *QueryOptions, args ...interface{}) (string, error) {
+	if c.revision >= proto.DBMS_MIN_PROTOCOL_VERSION_WITH_PARAMETERS {
+		parameters, rest, err := bindQueryParameters(c.server.Timezone, query, o.parameters, args...)
+		if err != nil {
+			return "", err
+		}
+		o.parameters, args = parameters, rest
+	}
+	return bind(c.server.Timezone, query, args...)
+}
@@ -0,0 +1,61 @@
+package clickhouse
+
+import (
+	"context"
+	"time"
+
+	"github.com/ClickHouse/clickhouse-go/v2/lib/proto"
+)
+
+// Connection::getTablesStatus
+// https://github.com/ClickHouse/ClickHouse/blob/master/src/Client/Connection.cpp
+func (c *connect) tablesStatus(ctx context.Context, tables []proto.QualifiedTableName) ([]proto.TableStatus, error) {
+	if deadline, ok := ctx.Deadline(); ok {
+		c.conn.SetDeadline(deadline)
+		defer c.conn.SetDeadline(time.Time{})
+	}
+	c.debugf("[tables status] -> %v", tables)
+	if c.err = c.encoder.Byte(proto.ClientTablesStatusRequest); c.err != nil {
+		return nil, c.err
+	}
+	if c.err = (&proto.TablesStatusRequest{Tables: tables}).Encode(c.encoder); c.err != nil {
+		return nil, c.err
+	}
+	if c.err = c.stream.EndPacket(); c.err != nil {
+		return nil, c.err
+	}
+	if c.err = c.encoder.Flush(); c.err != nil {
+		return nil, c.err
+	}
+	for {
+		packet, err := c.decoder.ReadByte()
+		if err != nil {
+			c.err = err
+			return nil, err
+		}
+		switch packet {
+		case proto.ServerTablesStatus:
+			var response proto.TablesStatusResponse
+			if c.err = response.Decode(c.decoder, c.revision); c.err != nil {
+				return nil, c.err
+			}
+			c.debugf("[tables status] <- %v", response.Tables)
+			// the server answers in an arbitrary order
+			status := make(map[proto.QualifiedTableName]proto.TableStatus, len(response.Tables))
+			for _, s := range response.Tables {
+				status[proto.QualifiedTableName{Database: s.Database, Table: s.Table}] = s
+			}
+			result := make([]proto.TableStatus, 0, len(tables))
+			for _, t := range tables {
+				if s, found := status[t]; found {
+					result = append(result, s)
+				}
+			}
+			return result, nil
+		default:
+			if err := c.handle(packet, (&QueryOptions{}).onProcess()); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
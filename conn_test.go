@@ -0,0 +1,147 @@
+package clickhouse
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/ClickHouse/clickhouse-go/v2/lib/binary"
+	"github.com/ClickHouse/clickhouse-go/v2/lib/compress"
+	chio "github.com/ClickHouse/clickhouse-go/v2/lib/io"
+	"github.com/ClickHouse/clickhouse-go/v2/lib/proto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDialContext(t *testing.T) {
+	var (
+		dialed  []string
+		errDial = errors.New("dial error")
+		opt     = &Options{
+			DialContext: func(ctx context.Context, addr string) (net.Conn, error) {
+				dialed = append(dialed, addr)
+				return nil, errDial
+			},
+		}
+	)
+	opt.setDefaults()
+	if _, err := dial(context.Background(), "host:9000", 1, opt, nil); assert.Equal(t, errDial, err) {
+		assert.Equal(t, []string{"host:9000"}, dialed)
+	}
+}
+
+func TestDialContextCancel(t *testing.T) {
+	opt := &Options{
+		DialTimeout: 50 * time.Millisecond,
+		DialContext: func(ctx context.Context, addr string) (net.Conn, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		},
+	}
+	opt.setDefaults()
+	start := time.Now()
+	// the dial gives up after DialTimeout
+	if _, err := dial(context.Background(), "host:9000", 1, opt, nil); assert.Equal(t, context.DeadlineExceeded, err) {
+		assert.Less(t, int64(time.Since(start)), int64(time.Second))
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := dial(ctx, "host:9000", 1, opt, nil)
+	assert.Equal(t, context.Canceled, err)
+}
+
+func TestPoolDialCancel(t *testing.T) {
+	opt := &Options{
+		Addr: []string{"a", "b"},
+		DialContext: func(ctx context.Context, addr string) (net.Conn, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		},
+	}
+	opt.setDefaults()
+	ch := &clickhouse{
+		opt:   opt,
+		stats: &poolStats{},
+		hosts: newHosts(opt),
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := ch.dial(ctx); assert.Equal(t, context.Canceled, err) {
+		// the hosts are not put in backoff when the caller gives up
+		for _, host := range ch.Stats().Hosts {
+			assert.True(t, host.Healthy)
+			assert.Equal(t, int64(0), host.DialFailures)
+		}
+	}
+}
+
+func TestDialHandshakeCancel(t *testing.T) {
+	opt := &Options{
+		DialTimeout: time.Hour,
+		DialContext: func(ctx context.Context, addr string) (net.Conn, error) {
+			client, server := net.Pipe()
+			// the server reads the hello and never replies
+			go io.Copy(ioutil.Discard, server)
+			return client, nil
+		},
+	}
+	opt.setDefaults()
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		cancel()
+	}()
+	start := time.Now()
+	if _, err := dial(ctx, "host:9000", 1, opt, nil); assert.Equal(t, context.Canceled, err) {
+		assert.Less(t, int64(time.Since(start)), int64(time.Second))
+	}
+}
+
+func TestExecQueryParameters(t *testing.T) {
+	exec := func(revision uint64) []byte {
+		var (
+			sent           bytes.Buffer
+			read           = make(chan struct{})
+			client, server = net.Pipe()
+			stream         = chio.NewStream(client, compress.NONE, 0, 0)
+			c              = &connect{
+				opt:      &Options{},
+				conn:     client,
+				debugf:   func(string, ...interface{}) {},
+				stream:   stream,
+				encoder:  binary.NewEncoder(stream),
+				decoder:  binary.NewDecoder(stream),
+				revision: revision,
+			}
+		)
+		defer server.Close()
+		go func() {
+			defer close(read)
+			io.Copy(&sent, server)
+		}()
+		go server.Write([]byte{proto.ServerEndOfStream})
+		err := c.exec(context.Background(), "INSERT INTO t VALUES ({id:UInt64}, @name)",
+			Named("id", uint64(42)),
+			Named("name", "x"),
+		)
+		client.Close()
+		<-read
+		if assert.NoError(t, err) {
+			return sent.Bytes()
+		}
+		return nil
+	}
+	// the body keeps the placeholder, the value is sent as the parameter after it
+	body := "INSERT INTO t VALUES ({id:UInt64}, 'x')"
+	expected := append(append([]byte{byte(len(body))}, body...), 2, 'i', 'd', 0x02, 4, '\'', '4', '2', '\'', 0)
+	assert.True(t, bytes.Contains(exec(proto.DBMS_MIN_PROTOCOL_VERSION_WITH_PARAMETERS), expected))
+	// the servers without the query parameters get the query as before
+	sent := exec(proto.DBMS_MIN_PROTOCOL_VERSION_WITH_PARAMETERS - 1)
+	if assert.True(t, bytes.Contains(sent, append([]byte{byte(len(body))}, body...))) {
+		assert.False(t, bytes.Contains(sent, []byte("'42'")))
+	}
+}
@@ -15,6 +15,11 @@ var _contextOptionKey = &QueryOptions{
 }
 
 type Settings map[string]interface{}
+
+// Parameters are the values of the {name:Type} placeholders of the query,
+// in the text format of the placeholder types (e.g. "[1, 2]" for Array(UInt8)).
+type Parameters map[string]string
+
 type (
 	QueryOption  func(*QueryOptions) error
 	QueryOptions struct {
@@ -27,8 +32,13 @@ type (
 			profileInfo   func(*ProfileInfo)
 			profileEvents func([]ProfileEvent)
 		}
-		settings Settings
-		external []*external.Table
+		settings   Settings
+		parameters Parameters
+		external   []*external.Table
+		autoFlush  struct {
+			rows  int
+			bytes int
+		}
 	}
 )
 
@@ -60,6 +70,24 @@ func WithSettings(settings Settings) QueryOption {
 	}
 }
 
+// WithParameters sets the values of the {name:Type} placeholders of the query. The parameters are
+// sent to the server along with the query, old servers get them substituted into the query text.
+func WithParameters(params Parameters) QueryOption {
+	return func(o *QueryOptions) error {
+		o.parameters = params
+		return nil
+	}
+}
+
+// WithAutoFlush makes the batches send the rows added by Append and AppendStruct to the server once they reach the number
+// of rows or the approximate size in bytes, 0 disables the limit. The rows of the batch are still inserted by a single INSERT.
+func WithAutoFlush(rows, bytes int) QueryOption {
+	return func(o *QueryOptions) error {
+		o.autoFlush.rows, o.autoFlush.bytes = rows, bytes
+		return nil
+	}
+}
+
 func WithLogs(fn func(*Log)) QueryOption {
 	return func(o *QueryOptions) error {
 		o.events.logs = fn
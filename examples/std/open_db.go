@@ -22,7 +22,7 @@ func example() error {
 		},
 		DialTimeout: 5 * time.Second,
 		Compression: &clickhouse.Compression{
-			clickhouse.CompressionLZ4,
+			Method: clickhouse.CompressionLZ4,
 		},
 		//Debug: true,
 	})
@@ -0,0 +1,393 @@
+package column
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/ClickHouse/clickhouse-go/v2/lib/binary"
+)
+
+// AggregateFunction holds the intermediate states of an aggregate function (uniqState, sumState, ...).
+// The states are not prefixed with their size on the wire, so only the functions with a known state
+// format are supported. Every state is available as an opaque []byte (scanning into *[]byte always
+// returns the raw state) that can be inserted as is; the states of count, sum, min, max, any, anyLast
+// and groupArray can also be scanned as typed values.
+// https://clickhouse.com/docs/en/sql-reference/data-types/aggregatefunction/
+type AggregateFunction struct {
+	chType Type
+	state  *aggregateState
+	data   [][]byte
+	values Interface
+}
+
+// aggregateState knows how to read a single state of the function.
+type aggregateState struct {
+	// read consumes one state and returns its typed value (ignored when values is nil).
+	read func(decoder *binary.Decoder) (interface{}, error)
+	// values creates the column holding the typed values of the states, nil if the function only supports raw states.
+	values func() (Interface, error)
+}
+
+func (col *AggregateFunction) parse(t Type) (_ Interface, err error) {
+	col.chType = t
+	params := splitTypeParams(t.params())
+	if len(params) != 0 {
+		// AggregateFunction(version, func, types...)
+		if _, err := strconv.ParseUint(params[0], 10, 64); err == nil {
+			params = params[1:]
+		}
+	}
+	if len(params) == 0 {
+		return &UnsupportedColumnType{
+			t: t,
+		}, nil
+	}
+	name := params[0]
+	if n := strings.Index(name, "("); n != -1 {
+		name = strings.TrimSpace(name[:n])
+	}
+	args := make([]Type, 0, len(params)-1)
+	for _, param := range params[1:] {
+		args = append(args, Type(param))
+	}
+	if col.state, err = newAggregateState(name, args); err != nil {
+		return nil, err
+	}
+	if col.state == nil {
+		return &UnsupportedColumnType{
+			t: t,
+		}, nil
+	}
+	if col.state.values != nil {
+		if col.values, err = col.state.values(); err != nil {
+			return nil, err
+		}
+	}
+	return col, nil
+}
+
+func (col *AggregateFunction) Type() Type {
+	return col.chType
+}
+
+func (col *AggregateFunction) ScanType() reflect.Type {
+	return scanTypeByte
+}
+
+func (col *AggregateFunction) Rows() int {
+	return len(col.data)
+}
+
+func (col *AggregateFunction) Row(i int, ptr bool) interface{} {
+	value := col.data[i]
+	if ptr {
+		return &value
+	}
+	return value
+}
+
+func (col *AggregateFunction) ScanRow(dest interface{}, row int) error {
+	switch d := dest.(type) {
+	case *[]byte:
+		*d = append((*d)[:0], col.data[row]...)
+	default:
+		if col.values == nil {
+			return &ColumnConverterError{
+				Op:   "ScanRow",
+				To:   fmt.Sprintf("%T", dest),
+				From: string(col.chType),
+				Hint: fmt.Sprintf("try using *%s", scanTypeByte),
+			}
+		}
+		return col.values.ScanRow(dest, row)
+	}
+	return nil
+}
+
+func (col *AggregateFunction) Append(v interface{}) (nulls []uint8, err error) {
+	switch v := v.(type) {
+	case [][]byte:
+		nulls = make([]uint8, len(v))
+		for _, v := range v {
+			if err := col.append(v); err != nil {
+				return nil, err
+			}
+		}
+	default:
+		return nil, &ColumnConverterError{
+			Op:   "Append",
+			To:   string(col.chType),
+			From: fmt.Sprintf("%T", v),
+		}
+	}
+	return
+}
+
+func (col *AggregateFunction) AppendRow(v interface{}) error {
+	switch v := v.(type) {
+	case []byte:
+		return col.append(v)
+	case *[]byte:
+		if v != nil {
+			return col.append(*v)
+		}
+	}
+	return &ColumnConverterError{
+		Op:   "AppendRow",
+		To:   string(col.chType),
+		From: fmt.Sprintf("%T", v),
+	}
+}
+
+func (col *AggregateFunction) Decode(decoder *binary.Decoder, rows int) error {
+	for i := 0; i < rows; i++ {
+		var (
+			reader = stateReader{decoder: decoder}
+			value  interface{}
+			err    error
+		)
+		if value, err = col.state.read(binary.NewDecoder(&reader)); err != nil {
+			return err
+		}
+		if col.values != nil {
+			if err := col.values.AppendRow(value); err != nil {
+				return err
+			}
+		}
+		col.data = append(col.data, reader.state.Bytes())
+	}
+	return nil
+}
+
+func (col *AggregateFunction) Encode(encoder *binary.Encoder) error {
+	for _, state := range col.data {
+		if err := encoder.Raw(state); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// append checks that v holds exactly one state of the function before adding it.
+func (col *AggregateFunction) append(v []byte) error {
+	reader := stateReader{decoder: binary.NewDecoder(bytes.NewReader(v))}
+	if _, err := col.state.read(binary.NewDecoder(&reader)); err != nil || reader.state.Len() != len(v) {
+		return &Error{
+			ColumnType: string(col.chType),
+			Err:        fmt.Errorf("invalid state of %d bytes", len(v)),
+		}
+	}
+	col.data = append(col.data, reader.state.Bytes())
+	return nil
+}
+
+// stateReader keeps a copy of the bytes read from the decoder.
+type stateReader struct {
+	state   bytes.Buffer
+	decoder *binary.Decoder
+}
+
+func (r *stateReader) Read(p []byte) (int, error) {
+	if err := r.decoder.Raw(p); err != nil {
+		return 0, err
+	}
+	r.state.Write(p)
+	return len(p), nil
+}
+
+func newAggregateState(name string, args []Type) (*aggregateState, error) {
+	switch name {
+	case "count":
+		return &aggregateState{
+			read: func(decoder *binary.Decoder) (interface{}, error) {
+				return decoder.Uvarint()
+			},
+			values: func() (Interface, error) {
+				return &UInt64{}, nil
+			},
+		}, nil
+	}
+	if len(args) != 1 {
+		return nil, nil
+	}
+	arg, err := args[0].Column()
+	if err != nil {
+		return nil, err
+	}
+	switch name {
+	case "sum":
+		var result Type
+		switch arg.(type) {
+		case *Int8, *Int16, *Int32, *Int64:
+			result = "Int64"
+		case *UInt8, *UInt16, *UInt32, *UInt64:
+			result = "UInt64"
+		case *Float32, *Float64:
+			result = "Float64"
+		case *BigInt:
+			result = args[0]
+		default:
+			return nil, nil
+		}
+		return &aggregateState{
+			read: func(decoder *binary.Decoder) (interface{}, error) {
+				return decodeValues(decoder, result, 1, func(col Interface) interface{} {
+					return col.Row(0, false)
+				})
+			},
+			values: result.Column,
+		}, nil
+	case "min", "max", "any", "anyLast":
+		values := func() (Interface, error) {
+			return Type(fmt.Sprintf("Nullable(%s)", args[0])).Column()
+		}
+		if _, ok := arg.(*String); ok {
+			return &aggregateState{
+				read: func(decoder *binary.Decoder) (interface{}, error) {
+					// the size includes the terminating zero byte, -1 means that there is no value
+					size, err := decoder.Int32()
+					if err != nil || size < 0 {
+						return nil, err
+					}
+					v, err := decoder.Fixed(int(size))
+					if err != nil || size == 0 {
+						return "", err
+					}
+					return string(v[:size-1]), nil
+				},
+				values: values,
+			}, nil
+		}
+		if !isFixedSize(arg) {
+			return nil, nil
+		}
+		return &aggregateState{
+			read: func(decoder *binary.Decoder) (interface{}, error) {
+				has, err := decoder.Bool()
+				if err != nil || !has {
+					return nil, err
+				}
+				return decodeValues(decoder, args[0], 1, func(col Interface) interface{} {
+					return col.Row(0, false)
+				})
+			},
+			values: values,
+		}, nil
+	case "groupArray":
+		if _, ok := arg.(*String); !ok && !isFixedSize(arg) {
+			return nil, nil
+		}
+		scanType := reflect.SliceOf(arg.ScanType())
+		return &aggregateState{
+			read: func(decoder *binary.Decoder) (interface{}, error) {
+				size, err := decoder.Uvarint()
+				if err != nil {
+					return nil, err
+				}
+				return decodeValues(decoder, args[0], int(size), func(col Interface) interface{} {
+					slice := reflect.MakeSlice(scanType, 0, col.Rows())
+					for i := 0; i < col.Rows(); i++ {
+						slice = reflect.Append(slice, reflect.ValueOf(col.Row(i, false)))
+					}
+					return slice.Interface()
+				})
+			},
+			values: func() (Interface, error) {
+				return Type(fmt.Sprintf("Array(%s)", args[0])).Column()
+			},
+		}, nil
+	case "uniq":
+		// UniquesHashSet: skip degree, number of hashes and the UInt32 hashes
+		return &aggregateState{
+			read: func(decoder *binary.Decoder) (interface{}, error) {
+				if _, err := decoder.UInt8(); err != nil {
+					return nil, err
+				}
+				size, err := decoder.Uvarint()
+				if err != nil {
+					return nil, err
+				}
+				_, err = decoder.Fixed(4 * int(size))
+				return nil, err
+			},
+		}, nil
+	case "quantile", "quantiles", "median":
+		if !isFixedSize(arg) {
+			return nil, nil
+		}
+		// ReservoirSampler: sample count, total values and min(sample count, total values) samples
+		return &aggregateState{
+			read: func(decoder *binary.Decoder) (interface{}, error) {
+				sampleCount, err := decoder.UInt64()
+				if err != nil {
+					return nil, err
+				}
+				totalValues, err := decoder.UInt64()
+				if err != nil {
+					return nil, err
+				}
+				if totalValues < sampleCount {
+					sampleCount = totalValues
+				}
+				return decodeValues(decoder, args[0], int(sampleCount), func(Interface) interface{} {
+					return nil
+				})
+			},
+		}, nil
+	}
+	return nil, nil
+}
+
+func decodeValues(decoder *binary.Decoder, t Type, rows int, value func(Interface) interface{}) (interface{}, error) {
+	col, err := t.Column()
+	if err != nil {
+		return nil, err
+	}
+	if rows != 0 {
+		if err := col.Decode(decoder, rows); err != nil {
+			return nil, err
+		}
+	}
+	return value(col), nil
+}
+
+// isFixedSize reports whether a value of the column is serialized as a fixed number of bytes.
+func isFixedSize(col Interface) bool {
+	switch col.(type) {
+	case *String, *Array, *Map, *Tuple, *Nested, *Nullable, *LowCardinality, *Nothing, *Interval,
+		*AggregateFunction, *SimpleAggregateFunction, *UnsupportedColumnType:
+		return false
+	}
+	return true
+}
+
+// splitTypeParams splits the parameters of a type on the top level commas.
+func splitTypeParams(params string) []string {
+	var (
+		brackets int
+		start    int
+		result   []string
+	)
+	for i, r := range params {
+		switch r {
+		case '(':
+			brackets++
+		case ')':
+			brackets--
+		case ',':
+			if brackets == 0 {
+				result = append(result, strings.TrimSpace(params[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	if last := strings.TrimSpace(params[start:]); len(last) != 0 {
+		result = append(result, last)
+	}
+	return result
+}
+
+var _ Interface = (*AggregateFunction)(nil)
@@ -0,0 +1,148 @@
+package column
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ClickHouse/clickhouse-go/v2/lib/binary"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAggregateFunction(t *testing.T) {
+	state := func(fn func(*binary.Encoder)) []byte {
+		var buffer bytes.Buffer
+		encoder := binary.NewEncoder(&buffer)
+		fn(encoder)
+		encoder.Flush()
+		return buffer.Bytes()
+	}
+	for _, asset := range []struct {
+		chType Type
+		states [][]byte
+		scan   func(t *testing.T, col Interface)
+	}{
+		{
+			chType: "AggregateFunction(count)",
+			states: [][]byte{state(func(e *binary.Encoder) { e.Uvarint(300) })},
+			scan: func(t *testing.T, col Interface) {
+				var v uint64
+				if assert.NoError(t, col.ScanRow(&v, 0)) {
+					assert.Equal(t, uint64(300), v)
+				}
+			},
+		},
+		{
+			chType: "AggregateFunction(sum, UInt32)",
+			states: [][]byte{state(func(e *binary.Encoder) { e.UInt64(42) })},
+			scan: func(t *testing.T, col Interface) {
+				var v uint64
+				if assert.NoError(t, col.ScanRow(&v, 0)) {
+					assert.Equal(t, uint64(42), v)
+				}
+			},
+		},
+		{
+			chType: "AggregateFunction(max, Int16)",
+			states: [][]byte{
+				state(func(e *binary.Encoder) { e.Bool(true); e.Int16(-7) }),
+				state(func(e *binary.Encoder) { e.Bool(false) }),
+			},
+			scan: func(t *testing.T, col Interface) {
+				var (
+					v1 int16
+					v2 *int16
+				)
+				if assert.NoError(t, col.ScanRow(&v1, 0)) && assert.NoError(t, col.ScanRow(&v2, 1)) {
+					assert.Equal(t, int16(-7), v1)
+					assert.Nil(t, v2)
+				}
+			},
+		},
+		{
+			chType: "AggregateFunction(any, String)",
+			states: [][]byte{
+				state(func(e *binary.Encoder) { e.Int32(4); e.Raw([]byte("abc\x00")) }),
+				state(func(e *binary.Encoder) { e.Int32(-1) }),
+			},
+			scan: func(t *testing.T, col Interface) {
+				var (
+					v1 string
+					v2 *string
+				)
+				if assert.NoError(t, col.ScanRow(&v1, 0)) && assert.NoError(t, col.ScanRow(&v2, 1)) {
+					assert.Equal(t, "abc", v1)
+					assert.Nil(t, v2)
+				}
+			},
+		},
+		{
+			chType: "AggregateFunction(groupArray(10), String)",
+			states: [][]byte{state(func(e *binary.Encoder) { e.Uvarint(2); e.String("A"); e.String("B") })},
+			scan: func(t *testing.T, col Interface) {
+				var v []string
+				if assert.NoError(t, col.ScanRow(&v, 0)) {
+					assert.Equal(t, []string{"A", "B"}, v)
+				}
+			},
+		},
+		{
+			chType: "AggregateFunction(uniq, UInt64)",
+			states: [][]byte{state(func(e *binary.Encoder) { e.UInt8(0); e.Uvarint(2); e.UInt32(1); e.UInt32(2) })},
+			scan: func(t *testing.T, col Interface) {
+				var v uint64
+				assert.Error(t, col.ScanRow(&v, 0))
+			},
+		},
+		{
+			chType: "AggregateFunction(quantile(0.5), Float64)",
+			states: [][]byte{state(func(e *binary.Encoder) { e.UInt64(8192); e.UInt64(2); e.Float64(1); e.Float64(2) })},
+		},
+	} {
+		var (
+			buffer  bytes.Buffer
+			decoder = binary.NewDecoder(&buffer)
+			encoder = binary.NewEncoder(&buffer)
+		)
+		col, err := asset.chType.Column()
+		if !assert.NoError(t, err) {
+			return
+		}
+		if _, err := col.Append(asset.states); !assert.NoError(t, err) {
+			return
+		}
+		if assert.NoError(t, col.Encode(encoder)) && assert.NoError(t, encoder.Flush()) {
+			col2, err := asset.chType.Column()
+			if !assert.NoError(t, err) {
+				return
+			}
+			if assert.NoError(t, col2.Decode(decoder, len(asset.states))) {
+				for i, expected := range asset.states {
+					var v []byte
+					if assert.NoError(t, col2.ScanRow(&v, i)) {
+						assert.Equal(t, expected, v, asset.chType)
+					}
+				}
+				if asset.scan != nil {
+					asset.scan(t, col2)
+				}
+			}
+		}
+	}
+}
+
+func TestAggregateFunctionInvalidState(t *testing.T) {
+	col, err := Type("AggregateFunction(sum, UInt64)").Column()
+	if assert.NoError(t, err) {
+		assert.Error(t, col.AppendRow([]byte{1, 2, 3}))
+		assert.Error(t, col.AppendRow(make([]byte, 9)))
+		assert.NoError(t, col.AppendRow(make([]byte, 8)))
+	}
+}
+
+func TestAggregateFunctionUnsupported(t *testing.T) {
+	col, err := Type("AggregateFunction(uniqExact, String)").Column()
+	if assert.NoError(t, err) {
+		_, ok := col.(*UnsupportedColumnType)
+		assert.True(t, ok)
+	}
+}
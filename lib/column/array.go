@@ -61,6 +61,11 @@ func (col *Array) Base() Interface {
 	return col.values
 }
 
+// Offsets returns the end offsets of the top level arrays.
+func (col *Array) Offsets() []uint64 {
+	return col.offsets[0].values
+}
+
 func (col *Array) Type() Type {
 	return col.chType
 }
@@ -0,0 +1,189 @@
+package column
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+
+	"github.com/ClickHouse/clickhouse-go/v2/lib/binary"
+)
+
+// BigInt is used for the 128 and 256 bit wide integer types (Int128, Int256, UInt128, UInt256).
+// Values are stored on the wire as little-endian (two's complement for the signed types).
+type BigInt struct {
+	size   int
+	chType Type
+	signed bool
+	data   []byte
+}
+
+func (col *BigInt) Type() Type {
+	return col.chType
+}
+
+func (col *BigInt) ScanType() reflect.Type {
+	return scanTypeBigInt
+}
+
+func (col *BigInt) Rows() int {
+	return len(col.data) / col.size
+}
+
+func (col *BigInt) Row(i int, ptr bool) interface{} {
+	value := col.row(i)
+	if ptr {
+		return value
+	}
+	return *value
+}
+
+func (col *BigInt) ScanRow(dest interface{}, row int) error {
+	switch d := dest.(type) {
+	case *big.Int:
+		d.Set(col.row(row))
+	case **big.Int:
+		*d = col.row(row)
+	default:
+		return &ColumnConverterError{
+			Op:   "ScanRow",
+			To:   fmt.Sprintf("%T", dest),
+			From: string(col.chType),
+			Hint: fmt.Sprintf("try using *%s", scanTypeBigInt),
+		}
+	}
+	return nil
+}
+
+func (col *BigInt) Append(v interface{}) (nulls []uint8, err error) {
+	size := len(col.data)
+	defer func() {
+		// a slice is appended entirely or not at all, so the columns of the batch keep the same length
+		if err != nil {
+			col.data = col.data[:size]
+		}
+	}()
+	switch v := v.(type) {
+	case []big.Int:
+		nulls = make([]uint8, len(v))
+		for i := range v {
+			if err := col.append(&v[i]); err != nil {
+				return nil, err
+			}
+		}
+	case []*big.Int:
+		nulls = make([]uint8, len(v))
+		for i, v := range v {
+			switch {
+			case v != nil:
+				if err := col.append(v); err != nil {
+					return nil, err
+				}
+			default:
+				col.data, nulls[i] = append(col.data, make([]byte, col.size)...), 1
+			}
+		}
+	default:
+		return nil, &ColumnConverterError{
+			Op:   "Append",
+			To:   string(col.chType),
+			From: fmt.Sprintf("%T", v),
+		}
+	}
+	return
+}
+
+func (col *BigInt) AppendRow(v interface{}) error {
+	switch v := v.(type) {
+	case big.Int:
+		return col.append(&v)
+	case *big.Int:
+		switch {
+		case v != nil:
+			return col.append(v)
+		default:
+			col.data = append(col.data, make([]byte, col.size)...)
+		}
+	case nil:
+		col.data = append(col.data, make([]byte, col.size)...)
+	default:
+		return &ColumnConverterError{
+			Op:   "AppendRow",
+			To:   string(col.chType),
+			From: fmt.Sprintf("%T", v),
+		}
+	}
+	return nil
+}
+
+func (col *BigInt) Decode(decoder *binary.Decoder, rows int) error {
+	col.data = make([]byte, col.size*rows)
+	return decoder.Raw(col.data)
+}
+
+func (col *BigInt) Encode(encoder *binary.Encoder) error {
+	return encoder.Raw(col.data)
+}
+
+func (col *BigInt) append(v *big.Int) error {
+	if !bigIntFits(v, col.size, col.signed) {
+		return &Error{
+			ColumnType: string(col.chType),
+			Err:        fmt.Errorf("value %s overflows %s", v, col.chType),
+		}
+	}
+	dest := make([]byte, col.size)
+	copyBigIntToRaw(dest, v)
+	col.data = append(col.data, dest...)
+	return nil
+}
+
+func (col *BigInt) row(i int) *big.Int {
+	return rawToBigInt(col.data[i*col.size:(i+1)*col.size], col.signed)
+}
+
+// rawToBigInt converts a little-endian value to big.Int. The source is left untouched.
+func rawToBigInt(v []byte, signed bool) *big.Int {
+	src := make([]byte, len(v))
+	copy(src, v)
+	// LittleEndian to BigEndian
+	endianSwap(src, false)
+	var lt = new(big.Int)
+	if signed && len(src) > 0 && src[0]&0x80 != 0 {
+		// [0] ^ will +1
+		for i := 0; i < len(src); i++ {
+			src[i] = ^src[i]
+		}
+		lt.SetBytes(src)
+		// neg ^ will -1
+		lt.Not(lt)
+	} else {
+		lt.SetBytes(src)
+	}
+	return lt
+}
+
+// copyBigIntToRaw writes v into dest as a little-endian two's complement value. v is left untouched.
+func copyBigIntToRaw(dest []byte, v *big.Int) {
+	var sign int
+	if v.Sign() < 0 {
+		new(big.Int).Not(v).FillBytes(dest)
+		sign = -1
+	} else {
+		v.FillBytes(dest)
+	}
+	endianSwap(dest, sign < 0)
+}
+
+func bigIntFits(v *big.Int, size int, signed bool) bool {
+	bits := size * 8
+	switch {
+	case !signed:
+		return v.Sign() >= 0 && v.BitLen() <= bits
+	case v.Sign() < 0:
+		// -2^(bits-1) is the smallest value
+		return new(big.Int).Not(v).BitLen() <= bits-1
+	}
+	return v.BitLen() <= bits-1
+}
+
+var _ Interface = (*BigInt)(nil)
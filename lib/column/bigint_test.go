@@ -0,0 +1,77 @@
+package column
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/ClickHouse/clickhouse-go/v2/lib/binary"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBigInt(t *testing.T) {
+	maxInt128, _ := new(big.Int).SetString("170141183460469231731687303715884105727", 10)
+	minInt128, _ := new(big.Int).SetString("-170141183460469231731687303715884105728", 10)
+	maxUInt256, _ := new(big.Int).SetString("115792089237316195423570985008687907853269984665640564039457584007913129639935", 10)
+	for _, asset := range []struct {
+		chType Type
+		values []*big.Int
+	}{
+		{chType: "Int128", values: []*big.Int{big.NewInt(0), big.NewInt(-1), big.NewInt(42), maxInt128, minInt128}},
+		{chType: "UInt128", values: []*big.Int{big.NewInt(0), big.NewInt(42), new(big.Int).Lsh(big.NewInt(1), 127)}},
+		{chType: "Int256", values: []*big.Int{big.NewInt(-42), new(big.Int).Neg(maxInt128), maxInt128}},
+		{chType: "UInt256", values: []*big.Int{big.NewInt(1), maxUInt256}},
+	} {
+		var (
+			buffer  bytes.Buffer
+			decoder = binary.NewDecoder(&buffer)
+			encoder = binary.NewEncoder(&buffer)
+		)
+		col, err := asset.chType.Column()
+		if !assert.NoError(t, err) {
+			return
+		}
+		for _, v := range asset.values {
+			if !assert.NoError(t, col.AppendRow(v)) {
+				return
+			}
+		}
+		if assert.NoError(t, col.Encode(encoder)) {
+			col2, err := asset.chType.Column()
+			if !assert.NoError(t, err) {
+				return
+			}
+			if assert.NoError(t, col2.Decode(decoder, len(asset.values))) {
+				for i, expected := range asset.values {
+					var v big.Int
+					if assert.NoError(t, col2.ScanRow(&v, i)) {
+						assert.Equal(t, 0, expected.Cmp(&v), "%s: expected %s got %s", asset.chType, expected, &v)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestBigIntOverflow(t *testing.T) {
+	for _, asset := range []struct {
+		chType Type
+		value  *big.Int
+	}{
+		{chType: "UInt128", value: big.NewInt(-1)},
+		{chType: "UInt128", value: new(big.Int).Lsh(big.NewInt(1), 128)},
+		{chType: "Int128", value: new(big.Int).Lsh(big.NewInt(1), 127)},
+		{chType: "Int256", value: new(big.Int).Neg(new(big.Int).Lsh(big.NewInt(1), 256))},
+	} {
+		col, err := asset.chType.Column()
+		if assert.NoError(t, err) {
+			assert.Error(t, col.AppendRow(asset.value), "%s: %s", asset.chType, asset.value)
+			// the slice which does not fit is not appended at all
+			_, err := col.Append([]*big.Int{big.NewInt(1), nil, asset.value})
+			assert.Error(t, err, "%s: %s", asset.chType, asset.value)
+			_, err = col.Append([]big.Int{*big.NewInt(1), *asset.value})
+			assert.Error(t, err, "%s: %s", asset.chType, asset.value)
+			assert.Equal(t, 0, col.Rows())
+		}
+	}
+}
@@ -0,0 +1,582 @@
+package column
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/ClickHouse/clickhouse-go/v2/lib/binary"
+)
+
+// The binary encoding of the data types, used by the values of the shared data of JSON and
+// of the SharedVariant of Dynamic (the type followed by the value in the RowBinary format).
+// https://clickhouse.com/docs/en/sql-reference/data-types/data-types-binary-encoding
+const (
+	binaryTypeNothing           = 0x00
+	binaryTypeDateTimeTZ        = 0x12
+	binaryTypeDateTime64        = 0x13
+	binaryTypeDateTime64TZ      = 0x14
+	binaryTypeFixedString       = 0x16
+	binaryTypeEnum8             = 0x17
+	binaryTypeEnum16            = 0x18
+	binaryTypeDecimal32         = 0x19
+	binaryTypeDecimal64         = 0x1A
+	binaryTypeDecimal128        = 0x1B
+	binaryTypeDecimal256        = 0x1C
+	binaryTypeArray             = 0x1E
+	binaryTypeTuple             = 0x1F
+	binaryTypeNamedTuple        = 0x20
+	binaryTypeInterval          = 0x22
+	binaryTypeNullable          = 0x23
+	binaryTypeLowCardinality    = 0x26
+	binaryTypeMap               = 0x27
+	binaryTypeVariant           = 0x2A
+	binaryTypeDynamic           = 0x2B
+	binaryTypeCustom            = 0x2C
+	binaryTypeNested            = 0x2F
+	binaryTypeJSON              = 0x30
+	binaryTypeJSONSerialization = 0
+)
+
+var (
+	binaryTypes = map[byte]Type{
+		binaryTypeNothing: "Nothing",
+		0x01:              "UInt8",
+		0x02:              "UInt16",
+		0x03:              "UInt32",
+		0x04:              "UInt64",
+		0x05:              "UInt128",
+		0x06:              "UInt256",
+		0x07:              "Int8",
+		0x08:              "Int16",
+		0x09:              "Int32",
+		0x0A:              "Int64",
+		0x0B:              "Int128",
+		0x0C:              "Int256",
+		0x0D:              "Float32",
+		0x0E:              "Float64",
+		0x0F:              "Date",
+		0x10:              "Date32",
+		0x11:              "DateTime",
+		0x15:              "String",
+		0x1D:              "UUID",
+		0x28:              "IPv4",
+		0x29:              "IPv6",
+		0x2D:              "Bool",
+	}
+	binaryTypeCodes = func() map[Type]byte {
+		codes := make(map[Type]byte, len(binaryTypes))
+		for code, t := range binaryTypes {
+			codes[t] = code
+		}
+		return codes
+	}()
+	intervalKinds = []string{
+		"Nanosecond", "Microsecond", "Millisecond", "Second", "Minute", "Hour", "Day", "Week", "Month", "Quarter", "Year",
+	}
+)
+
+// decodeBinaryType reads a binary encoded data type.
+func decodeBinaryType(decoder *binary.Decoder) (Type, error) {
+	code, err := decoder.UInt8()
+	if err != nil {
+		return "", err
+	}
+	if t, found := binaryTypes[code]; found {
+		return t, nil
+	}
+	switch code {
+	case binaryTypeDateTimeTZ:
+		tz, err := decoder.String()
+		if err != nil {
+			return "", err
+		}
+		return Type(fmt.Sprintf("DateTime('%s')", tz)), nil
+	case binaryTypeDateTime64, binaryTypeDateTime64TZ:
+		precision, err := decoder.UInt8()
+		if err != nil {
+			return "", err
+		}
+		if code == binaryTypeDateTime64 {
+			return Type(fmt.Sprintf("DateTime64(%d)", precision)), nil
+		}
+		tz, err := decoder.String()
+		if err != nil {
+			return "", err
+		}
+		return Type(fmt.Sprintf("DateTime64(%d, '%s')", precision, tz)), nil
+	case binaryTypeFixedString:
+		size, err := decoder.Uvarint()
+		if err != nil {
+			return "", err
+		}
+		return Type(fmt.Sprintf("FixedString(%d)", size)), nil
+	case binaryTypeEnum8, binaryTypeEnum16:
+		n, err := decoder.Uvarint()
+		if err != nil {
+			return "", err
+		}
+		elements := make([]string, 0, n)
+		for i := 0; i < int(n); i++ {
+			name, err := decoder.String()
+			if err != nil {
+				return "", err
+			}
+			var value int64
+			if code == binaryTypeEnum8 {
+				v, err := decoder.Int8()
+				if err != nil {
+					return "", err
+				}
+				value = int64(v)
+			} else {
+				v, err := decoder.Int16()
+				if err != nil {
+					return "", err
+				}
+				value = int64(v)
+			}
+			elements = append(elements, fmt.Sprintf("'%s' = %d", strings.ReplaceAll(name, "'", "\\'"), value))
+		}
+		name := "Enum8"
+		if code == binaryTypeEnum16 {
+			name = "Enum16"
+		}
+		return Type(fmt.Sprintf("%s(%s)", name, strings.Join(elements, ", "))), nil
+	case binaryTypeDecimal32, binaryTypeDecimal64, binaryTypeDecimal128, binaryTypeDecimal256:
+		precision, err := decoder.UInt8()
+		if err != nil {
+			return "", err
+		}
+		scale, err := decoder.UInt8()
+		if err != nil {
+			return "", err
+		}
+		return Type(fmt.Sprintf("Decimal(%d, %d)", precision, scale)), nil
+	case binaryTypeArray, binaryTypeNullable, binaryTypeLowCardinality:
+		nested, err := decodeBinaryType(decoder)
+		if err != nil {
+			return "", err
+		}
+		name := map[byte]string{
+			binaryTypeArray:          "Array",
+			binaryTypeNullable:       "Nullable",
+			binaryTypeLowCardinality: "LowCardinality",
+		}[code]
+		return Type(fmt.Sprintf("%s(%s)", name, nested)), nil
+	case binaryTypeTuple, binaryTypeVariant:
+		elements, err := decodeBinaryTypes(decoder, false)
+		if err != nil {
+			return "", err
+		}
+		name := "Tuple"
+		if code == binaryTypeVariant {
+			name = "Variant"
+		}
+		return Type(fmt.Sprintf("%s(%s)", name, strings.Join(elements, ", "))), nil
+	case binaryTypeNamedTuple, binaryTypeNested:
+		elements, err := decodeBinaryTypes(decoder, true)
+		if err != nil {
+			return "", err
+		}
+		name := "Tuple"
+		if code == binaryTypeNested {
+			name = "Nested"
+		}
+		return Type(fmt.Sprintf("%s(%s)", name, strings.Join(elements, ", "))), nil
+	case binaryTypeInterval:
+		kind, err := decoder.UInt8()
+		if err != nil {
+			return "", err
+		}
+		if int(kind) >= len(intervalKinds) {
+			return "", fmt.Errorf("unknown interval kind %d", kind)
+		}
+		return Type("Interval" + intervalKinds[kind]), nil
+	case binaryTypeMap:
+		key, err := decodeBinaryType(decoder)
+		if err != nil {
+			return "", err
+		}
+		value, err := decodeBinaryType(decoder)
+		if err != nil {
+			return "", err
+		}
+		return Type(fmt.Sprintf("Map(%s, %s)", key, value)), nil
+	case binaryTypeDynamic:
+		maxTypes, err := decoder.UInt8()
+		if err != nil {
+			return "", err
+		}
+		return Type(fmt.Sprintf("Dynamic(max_types=%d)", maxTypes)), nil
+	case binaryTypeCustom:
+		name, err := decoder.String()
+		if err != nil {
+			return "", err
+		}
+		return Type(name), nil
+	case binaryTypeJSON:
+		return decodeBinaryJSONType(decoder)
+	}
+	return "", fmt.Errorf("unsupported binary type code 0x%02X", code)
+}
+
+func decodeBinaryTypes(decoder *binary.Decoder, named bool) ([]string, error) {
+	n, err := decoder.Uvarint()
+	if err != nil {
+		return nil, err
+	}
+	elements := make([]string, 0, n)
+	for i := 0; i < int(n); i++ {
+		var name string
+		if named {
+			if name, err = decoder.String(); err != nil {
+				return nil, err
+			}
+		}
+		t, err := decodeBinaryType(decoder)
+		if err != nil {
+			return nil, err
+		}
+		if named {
+			elements = append(elements, fmt.Sprintf("%s %s", name, t))
+			continue
+		}
+		elements = append(elements, string(t))
+	}
+	return elements, nil
+}
+
+func decodeBinaryJSONType(decoder *binary.Decoder) (Type, error) {
+	version, err := decoder.UInt8()
+	if err != nil {
+		return "", err
+	}
+	if version != binaryTypeJSONSerialization {
+		return "", fmt.Errorf("unsupported JSON type serialization version %d", version)
+	}
+	maxPaths, err := decoder.Uvarint()
+	if err != nil {
+		return "", err
+	}
+	maxTypes, err := decoder.UInt8()
+	if err != nil {
+		return "", err
+	}
+	params := []string{
+		fmt.Sprintf("max_dynamic_paths=%d", maxPaths),
+		fmt.Sprintf("max_dynamic_types=%d", maxTypes),
+	}
+	typed, err := decodeBinaryTypes(decoder, true)
+	if err != nil {
+		return "", err
+	}
+	params = append(params, typed...)
+	for _, prefix := range []string{"SKIP %s", "SKIP REGEXP '%s'"} {
+		n, err := decoder.Uvarint()
+		if err != nil {
+			return "", err
+		}
+		for i := 0; i < int(n); i++ {
+			path, err := decoder.String()
+			if err != nil {
+				return "", err
+			}
+			params = append(params, fmt.Sprintf(prefix, path))
+		}
+	}
+	return Type(fmt.Sprintf("JSON(%s)", strings.Join(params, ", "))), nil
+}
+
+// encodeBinaryType writes the binary encoding of the types inferred from the JSON values.
+func encodeBinaryType(encoder *binary.Encoder, t Type) error {
+	if code, found := binaryTypeCodes[t]; found {
+		return encoder.UInt8(code)
+	}
+	switch {
+	case strings.HasPrefix(string(t), "Array("):
+		if err := encoder.UInt8(binaryTypeArray); err != nil {
+			return err
+		}
+		return encodeBinaryType(encoder, Type(t.params()))
+	case strings.HasPrefix(string(t), "Nullable("):
+		if err := encoder.UInt8(binaryTypeNullable); err != nil {
+			return err
+		}
+		return encodeBinaryType(encoder, Type(t.params()))
+	case strings.HasPrefix(string(t), "JSON"):
+		col, err := (&JSON{}).parse(t)
+		if err != nil {
+			return err
+		}
+		object, ok := col.(*JSON)
+		if !ok || len(object.typedPaths) != 0 || len(object.skipPaths) != 0 || len(object.skipRegexps) != 0 {
+			break
+		}
+		if err := encoder.UInt8(binaryTypeJSON); err != nil {
+			return err
+		}
+		if err := encoder.UInt8(binaryTypeJSONSerialization); err != nil {
+			return err
+		}
+		if err := encoder.Uvarint(uint64(object.maxDynamicPaths)); err != nil {
+			return err
+		}
+		if err := encoder.UInt8(uint8(object.maxDynamicTypes)); err != nil {
+			return err
+		}
+		// no typed paths, no SKIP paths and no SKIP REGEXP
+		for i := 0; i < 3; i++ {
+			if err := encoder.Uvarint(0); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("unsupported binary encoding of %s", t)
+}
+
+// encodeBinaryValue writes the value of the type (as converted by convertJSON) in the RowBinary format.
+func encodeBinaryValue(encoder *binary.Encoder, t Type, v interface{}) error {
+	switch {
+	case strings.HasPrefix(string(t), "Nullable("):
+		value := reflect.ValueOf(v)
+		if !value.IsValid() || (value.Kind() == reflect.Ptr && value.IsNil()) {
+			return encoder.UInt8(1)
+		}
+		if err := encoder.UInt8(0); err != nil {
+			return err
+		}
+		return encodeBinaryValue(encoder, Type(t.params()), reflect.Indirect(value).Interface())
+	case strings.HasPrefix(string(t), "Array("):
+		value := reflect.ValueOf(v)
+		if value.Kind() != reflect.Slice {
+			return fmt.Errorf("unexpected value %T of %s", v, t)
+		}
+		if err := encoder.Uvarint(uint64(value.Len())); err != nil {
+			return err
+		}
+		for i := 0; i < value.Len(); i++ {
+			if err := encodeBinaryValue(encoder, Type(t.params()), value.Index(i).Interface()); err != nil {
+				return err
+			}
+		}
+		return nil
+	case strings.HasPrefix(string(t), "JSON"):
+		object, ok := v.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("unexpected value %T of %s", v, t)
+		}
+		paths := make(map[string]interface{})
+		(&JSON{}).flatten("", object, paths)
+		keys := sortedKeys(paths)
+		if err := encoder.Uvarint(uint64(len(keys))); err != nil {
+			return err
+		}
+		for _, path := range keys {
+			if err := encoder.String(path); err != nil {
+				return err
+			}
+			if err := encodeBinaryJSONValue(encoder, paths[path]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	col, err := t.Column()
+	if err != nil {
+		return err
+	}
+	if err := col.AppendRow(v); err != nil {
+		return err
+	}
+	return col.Encode(encoder)
+}
+
+// encodeBinaryJSONValue writes the JSON value as the binary encoded inferred type followed by the value.
+func encodeBinaryJSONValue(encoder *binary.Encoder, v interface{}) error {
+	t, err := jsonValueType(v)
+	if err != nil {
+		return err
+	}
+	value, err := convertJSON(v, t)
+	if err != nil {
+		return err
+	}
+	if err := encodeBinaryType(encoder, t); err != nil {
+		return err
+	}
+	return encodeBinaryValue(encoder, t, value)
+}
+
+// decodeBinaryValue decodes a binary encoded type followed by the value in the RowBinary format.
+func decodeBinaryValue(data string) (interface{}, error) {
+	return readBinaryDynamic(binary.NewDecoder(bytes.NewReader([]byte(data))))
+}
+
+// readBinaryDynamic reads a binary encoded type followed by the value, NULL has the Nothing type.
+func readBinaryDynamic(decoder *binary.Decoder) (interface{}, error) {
+	t, err := decodeBinaryType(decoder)
+	if err != nil {
+		return nil, err
+	}
+	if t == "Nothing" {
+		return nil, nil
+	}
+	col, err := t.Column()
+	if err != nil {
+		return nil, err
+	}
+	value, err := readBinaryValue(decoder, col)
+	if err != nil {
+		return nil, err
+	}
+	return rowValue(value), nil
+}
+
+// readBinaryValue reads a value in the RowBinary format, the value has the scan type of the column.
+func readBinaryValue(decoder *binary.Decoder, col Interface) (reflect.Value, error) {
+	switch c := col.(type) {
+	case *UnsupportedColumnType, *Nothing:
+		return reflect.Value{}, &UnsupportedColumnType{
+			t: col.Type(),
+		}
+	case *Nullable:
+		null, err := decoder.UInt8()
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		if null != 0 {
+			return reflect.Zero(c.ScanType()), nil
+		}
+		value, err := readBinaryValue(decoder, c.Base())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		ptr := reflect.New(value.Type())
+		ptr.Elem().Set(value)
+		return ptr, nil
+	case *LowCardinality:
+		nested, err := Type(c.chType.params()).Column()
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return readBinaryValue(decoder, nested)
+	case *Nested:
+		return readBinaryValue(decoder, c.Interface)
+	case *Array:
+		elem, err := arrayElemType(c.chType).Column()
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		n, err := decoder.Uvarint()
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		slice := reflect.MakeSlice(c.ScanType(), 0, int(n))
+		for i := 0; i < int(n); i++ {
+			value, err := readBinaryValue(decoder, elem)
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			slice = reflect.Append(slice, value)
+		}
+		return slice, nil
+	case *Tuple:
+		tuple := make([]interface{}, 0, len(c.columns))
+		for _, elem := range c.columns {
+			value, err := readBinaryValue(decoder, elem)
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			tuple = append(tuple, rowValue(value))
+		}
+		return reflect.ValueOf(tuple), nil
+	case *Map:
+		n, err := decoder.Uvarint()
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		m := reflect.MakeMapWithSize(c.ScanType(), int(n))
+		for i := 0; i < int(n); i++ {
+			key, err := readBinaryValue(decoder, c.keys)
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			value, err := readBinaryValue(decoder, c.values)
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			if c.bigIntKeys {
+				key = reflect.ValueOf(fmt.Sprint(key.Interface()))
+			}
+			m.SetMapIndex(key, value)
+		}
+		return m, nil
+	case *Variant:
+		d, err := decoder.UInt8()
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		value := reflect.New(scanTypeAny).Elem()
+		switch {
+		case d == NullVariantDiscriminator:
+		case int(d) < len(c.columns):
+			v, err := readBinaryValue(decoder, c.columns[d])
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			value.Set(v)
+		default:
+			return reflect.Value{}, fmt.Errorf("invalid discriminator %d", d)
+		}
+		return value, nil
+	case *Dynamic:
+		v, err := readBinaryDynamic(decoder)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		value := reflect.New(scanTypeAny).Elem()
+		if v != nil {
+			value.Set(reflect.ValueOf(v))
+		}
+		return value, nil
+	case *JSON:
+		return c.readBinaryValue(decoder)
+	}
+	row := col.Rows()
+	if err := col.Decode(decoder, 1); err != nil {
+		return reflect.Value{}, err
+	}
+	return reflect.ValueOf(col.Row(row, false)), nil
+}
+
+// arrayElemType returns the type of the elements of the Array (or of the geo types built on Array).
+func arrayElemType(t Type) Type {
+	switch t {
+	case "Ring":
+		return "Point"
+	case "Polygon":
+		return "Ring"
+	case "MultiPolygon":
+		return "Polygon"
+	}
+	return Type(t.params())
+}
+
+// rowValue returns the value as returned by Row of the columns, NULL is nil instead of a nil pointer.
+func rowValue(value reflect.Value) interface{} {
+	if !value.IsValid() {
+		return nil
+	}
+	if value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return nil
+		}
+		if value.Kind() == reflect.Ptr {
+			return value.Elem().Interface()
+		}
+	}
+	return value.Interface()
+}
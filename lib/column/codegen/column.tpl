@@ -9,15 +9,25 @@ import (
 	"fmt"
 	"time"
 	"net"
+	"math/big"
 	"github.com/google/uuid"
 	"github.com/shopspring/decimal"
+	"github.com/ClickHouse/clickhouse-go/v2/lib/geo"
 )
 
 func (t Type) Column() (Interface, error) {
 	switch t {
-{{- range . }}
+{{- range .Types }}
 	case "{{ .ChType }}":
 		return &{{ .ChType }}{}, nil
+{{- end }}
+{{- range .BigInt }}
+	case "{{ .ChType }}":
+		return &BigInt{
+			size:   {{ .Size }} / 8,
+			chType: t,
+			signed: {{ .Signed }},
+		}, nil
 {{- end }}
 	case "IPv4":
 		return &IPv4{}, nil
@@ -35,14 +45,22 @@ func (t Type) Column() (Interface, error) {
 		return &Nothing{}, nil
 	case "String":
 		return &String{}, nil
+	case "Object('json')":
+		return &Object{chType: t}, nil
+	case "Point":
+		return &Point{}, nil
+	case "Ring", "Polygon", "MultiPolygon":
+		return geoArray(t)
 	}
 
 	switch strType := string(t); {
 	case strings.HasPrefix(string(t), "Map("):
 		return (&Map{}).parse(t)
+	case strings.HasPrefix(string(t), "Nested("):
+		return (&Nested{}).parse(t)
 	case strings.HasPrefix(string(t), "Tuple("):
 		return (&Tuple{}).parse(t)
-	case strings.HasPrefix(string(t), "Decimal("):
+	case strings.HasPrefix(string(t), "Decimal"):
 		return (&Decimal{}).parse(t)
 	case strings.HasPrefix(string(t), "Array("):
 		return (&Array{}).parse(t)
@@ -54,6 +72,14 @@ func (t Type) Column() (Interface, error) {
 		return (&FixedString{}).parse(t)
 	case strings.HasPrefix(string(t), "LowCardinality"):
 		return (&LowCardinality{}).parse(t)
+	case strings.HasPrefix(string(t), "Variant("):
+		return (&Variant{}).parse(t)
+	case strings.HasPrefix(string(t), "Dynamic"):
+		return (&Dynamic{}).parse(t)
+	case strings.HasPrefix(string(t), "JSON"):
+		return (&JSON{}).parse(t)
+	case strings.HasPrefix(string(t), "AggregateFunction("):
+		return (&AggregateFunction{}).parse(t)
 	case strings.HasPrefix(string(t), "SimpleAggregateFunction"):
 		return (&SimpleAggregateFunction{}).parse(t)
 	case strings.HasPrefix(string(t), "Enum8") || strings.HasPrefix(string(t), "Enum16"):
@@ -69,19 +95,19 @@ func (t Type) Column() (Interface, error) {
 }
 
 type (
-{{- range . }}
+{{- range .Types }}
 	{{ .ChType }} []{{ .GoType }}
 {{- end }}
 )
 
 var (
-{{- range . }}
+{{- range .Types }}
 	_ Interface = (*{{ .ChType }})(nil)
 {{- end }}
 )
 
 var (
-	{{- range . }}
+	{{- range .Types }}
 		scanType{{ .ChType }} = reflect.TypeOf({{ .GoType }}(0))
 	{{- end }}
 		scanTypeIP      = reflect.TypeOf(net.IP{})
@@ -92,9 +118,16 @@ var (
 		scanTypeSlice   = reflect.TypeOf([]interface{}{})
 		scanTypeString  = reflect.TypeOf("")
 		scanTypeDecimal = reflect.TypeOf(decimal.Decimal{})
+		scanTypeBigInt  = reflect.TypeOf(big.Int{})
+		scanTypeAny          = reflect.TypeOf((*interface{})(nil)).Elem()
+		scanTypeJSON         = reflect.TypeOf(map[string]interface{}{})
+		scanTypePoint        = reflect.TypeOf(geo.Point{})
+		scanTypeRing         = reflect.TypeOf(geo.Ring{})
+		scanTypePolygon      = reflect.TypeOf(geo.Polygon{})
+		scanTypeMultiPolygon = reflect.TypeOf(geo.MultiPolygon{})
 	)
 
-{{- range . }}
+{{- range .Types }}
 
 func (col *{{ .ChType }}) Type() Type {
 	return "{{ .ChType }}"
@@ -10,7 +10,7 @@ import (
 )
 
 
-{{- range . }}
+{{- range .Types }}
 
 func (col *{{ .ChType }}) Decode(decoder *binary.Decoder, rows int) error {
 	for i := 0; i < rows; i++ {
@@ -12,7 +12,7 @@ import (
 )
 
 
-{{- range . }}
+{{- range .Types }}
 
 func (col *{{ .ChType }}) Decode(decoder *binary.Decoder, rows int) error {
 	if rows == 0 {
@@ -20,11 +20,13 @@ var (
 	columnUnsafeSrc string
 )
 var (
-	types []_type
+	types       []_type
+	bigIntTypes []_type
 )
 
 type _type struct {
 	Size   int
+	Signed bool
 	ChType string
 	GoType string
 }
@@ -48,9 +50,22 @@ func init() {
 			GoType: fmt.Sprintf("float%d", size),
 		})
 	}
+	for _, size := range []int{128, 256} {
+		bigIntTypes = append(bigIntTypes, _type{
+			Size:   size,
+			Signed: true,
+			ChType: fmt.Sprintf("Int%d", size),
+		}, _type{
+			Size:   size,
+			ChType: fmt.Sprintf("UInt%d", size),
+		})
+	}
 	sort.Slice(types, func(i, j int) bool {
 		return sequenceKey(types[i].ChType) < sequenceKey(types[j].ChType)
 	})
+	sort.Slice(bigIntTypes, func(i, j int) bool {
+		return sequenceKey(bigIntTypes[i].ChType) < sequenceKey(bigIntTypes[j].ChType)
+	})
 }
 func write(name string, v interface{}, t *template.Template) error {
 	out := new(bytes.Buffer)
@@ -69,12 +84,19 @@ func write(name string, v interface{}, t *template.Template) error {
 }
 
 func main() {
+	data := struct {
+		Types  []_type
+		BigInt []_type
+	}{
+		Types:  types,
+		BigInt: bigIntTypes,
+	}
 	for name, tpl := range map[string]*template.Template{
 		"column_gen":        template.Must(template.New("column").Parse(columnSrc)),
 		"column_safe_gen":   template.Must(template.New("column").Parse(columnSafeSrc)),
 		"column_unsafe_gen": template.Must(template.New("column").Parse(columnUnsafeSrc)),
 	} {
-		if err := write(name, types, tpl); err != nil {
+		if err := write(name, data, tpl); err != nil {
 			log.Fatal(err)
 		}
 	}
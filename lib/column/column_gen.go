@@ -5,8 +5,10 @@ package column
 
 import (
 	"fmt"
+	"github.com/ClickHouse/clickhouse-go/v2/lib/geo"
 	"github.com/google/uuid"
 	"github.com/shopspring/decimal"
+	"math/big"
 	"net"
 	"reflect"
 	"strings"
@@ -35,6 +37,30 @@ func (t Type) Column() (Interface, error) {
 		return &UInt32{}, nil
 	case "UInt64":
 		return &UInt64{}, nil
+	case "Int128":
+		return &BigInt{
+			size:   128 / 8,
+			chType: t,
+			signed: true,
+		}, nil
+	case "Int256":
+		return &BigInt{
+			size:   256 / 8,
+			chType: t,
+			signed: true,
+		}, nil
+	case "UInt128":
+		return &BigInt{
+			size:   128 / 8,
+			chType: t,
+			signed: false,
+		}, nil
+	case "UInt256":
+		return &BigInt{
+			size:   256 / 8,
+			chType: t,
+			signed: false,
+		}, nil
 	case "IPv4":
 		return &IPv4{}, nil
 	case "IPv6":
@@ -51,14 +77,22 @@ func (t Type) Column() (Interface, error) {
 		return &Nothing{}, nil
 	case "String":
 		return &String{}, nil
+	case "Object('json')":
+		return &Object{chType: t}, nil
+	case "Point":
+		return &Point{}, nil
+	case "Ring", "Polygon", "MultiPolygon":
+		return geoArray(t)
 	}
 
 	switch strType := string(t); {
 	case strings.HasPrefix(string(t), "Map("):
 		return (&Map{}).parse(t)
+	case strings.HasPrefix(string(t), "Nested("):
+		return (&Nested{}).parse(t)
 	case strings.HasPrefix(string(t), "Tuple("):
 		return (&Tuple{}).parse(t)
-	case strings.HasPrefix(string(t), "Decimal("):
+	case strings.HasPrefix(string(t), "Decimal"):
 		return (&Decimal{}).parse(t)
 	case strings.HasPrefix(string(t), "Array("):
 		return (&Array{}).parse(t)
@@ -70,6 +104,14 @@ func (t Type) Column() (Interface, error) {
 		return (&FixedString{}).parse(t)
 	case strings.HasPrefix(string(t), "LowCardinality"):
 		return (&LowCardinality{}).parse(t)
+	case strings.HasPrefix(string(t), "Variant("):
+		return (&Variant{}).parse(t)
+	case strings.HasPrefix(string(t), "Dynamic"):
+		return (&Dynamic{}).parse(t)
+	case strings.HasPrefix(string(t), "JSON"):
+		return (&JSON{}).parse(t)
+	case strings.HasPrefix(string(t), "AggregateFunction("):
+		return (&AggregateFunction{}).parse(t)
 	case strings.HasPrefix(string(t), "SimpleAggregateFunction"):
 		return (&SimpleAggregateFunction{}).parse(t)
 	case strings.HasPrefix(string(t), "Enum8") || strings.HasPrefix(string(t), "Enum16"):
@@ -111,24 +153,31 @@ var (
 )
 
 var (
-	scanTypeFloat32 = reflect.TypeOf(float32(0))
-	scanTypeFloat64 = reflect.TypeOf(float64(0))
-	scanTypeInt8    = reflect.TypeOf(int8(0))
-	scanTypeInt16   = reflect.TypeOf(int16(0))
-	scanTypeInt32   = reflect.TypeOf(int32(0))
-	scanTypeInt64   = reflect.TypeOf(int64(0))
-	scanTypeUInt8   = reflect.TypeOf(uint8(0))
-	scanTypeUInt16  = reflect.TypeOf(uint16(0))
-	scanTypeUInt32  = reflect.TypeOf(uint32(0))
-	scanTypeUInt64  = reflect.TypeOf(uint64(0))
-	scanTypeIP      = reflect.TypeOf(net.IP{})
-	scanTypeBool    = reflect.TypeOf(true)
-	scanTypeByte    = reflect.TypeOf([]byte{})
-	scanTypeUUID    = reflect.TypeOf(uuid.UUID{})
-	scanTypeTime    = reflect.TypeOf(time.Time{})
-	scanTypeSlice   = reflect.TypeOf([]interface{}{})
-	scanTypeString  = reflect.TypeOf("")
-	scanTypeDecimal = reflect.TypeOf(decimal.Decimal{})
+	scanTypeFloat32      = reflect.TypeOf(float32(0))
+	scanTypeFloat64      = reflect.TypeOf(float64(0))
+	scanTypeInt8         = reflect.TypeOf(int8(0))
+	scanTypeInt16        = reflect.TypeOf(int16(0))
+	scanTypeInt32        = reflect.TypeOf(int32(0))
+	scanTypeInt64        = reflect.TypeOf(int64(0))
+	scanTypeUInt8        = reflect.TypeOf(uint8(0))
+	scanTypeUInt16       = reflect.TypeOf(uint16(0))
+	scanTypeUInt32       = reflect.TypeOf(uint32(0))
+	scanTypeUInt64       = reflect.TypeOf(uint64(0))
+	scanTypeIP           = reflect.TypeOf(net.IP{})
+	scanTypeBool         = reflect.TypeOf(true)
+	scanTypeByte         = reflect.TypeOf([]byte{})
+	scanTypeUUID         = reflect.TypeOf(uuid.UUID{})
+	scanTypeTime         = reflect.TypeOf(time.Time{})
+	scanTypeSlice        = reflect.TypeOf([]interface{}{})
+	scanTypeString       = reflect.TypeOf("")
+	scanTypeDecimal      = reflect.TypeOf(decimal.Decimal{})
+	scanTypeBigInt       = reflect.TypeOf(big.Int{})
+	scanTypeAny          = reflect.TypeOf((*interface{})(nil)).Elem()
+	scanTypeJSON         = reflect.TypeOf(map[string]interface{}{})
+	scanTypePoint        = reflect.TypeOf(geo.Point{})
+	scanTypeRing         = reflect.TypeOf(geo.Ring{})
+	scanTypePolygon      = reflect.TypeOf(geo.Polygon{})
+	scanTypeMultiPolygon = reflect.TypeOf(geo.MultiPolygon{})
 )
 
 func (col *Float32) Type() Type {
@@ -17,25 +17,47 @@ type Decimal struct {
 	scale     int
 	nobits    int // its domain is {32, 64, 128, 256}
 	precision int
-	values    []decimal.Decimal
+	// max is 10^precision, the bound of the absolute value of the scaled values
+	max    *big.Int
+	values []decimal.Decimal
 }
 
 func (col *Decimal) parse(t Type) (_ *Decimal, err error) {
 	col.chType = t
 	params := strings.Split(t.params(), ",")
-	if len(params) != 2 {
-		return nil, fmt.Errorf("invalid Decimal format: '%s'", t)
+	switch {
+	case strings.HasPrefix(string(t), "Decimal("):
+		if len(params) != 2 {
+			return nil, fmt.Errorf("invalid Decimal format: '%s'", t)
+		}
+		params[0] = strings.TrimSpace(params[0])
+		if col.precision, err = strconv.Atoi(params[0]); err != nil {
+			return nil, fmt.Errorf("'%s' is not Decimal type: %s", t, err)
+		} else if col.precision < 1 {
+			return nil, errors.New("wrong precision of Decimal type")
+		}
+		params = params[1:]
+	default:
+		// Decimal32(S), Decimal64(S), Decimal128(S) and Decimal256(S) are aliases with the max precision of the type
+		if len(params) != 1 {
+			return nil, fmt.Errorf("invalid Decimal format: '%s'", t)
+		}
+		switch {
+		case strings.HasPrefix(string(t), "Decimal32("):
+			col.precision = 9
+		case strings.HasPrefix(string(t), "Decimal64("):
+			col.precision = 18
+		case strings.HasPrefix(string(t), "Decimal128("):
+			col.precision = 38
+		case strings.HasPrefix(string(t), "Decimal256("):
+			col.precision = 76
+		default:
+			return nil, fmt.Errorf("invalid Decimal format: '%s'", t)
+		}
 	}
 	params[0] = strings.TrimSpace(params[0])
-	params[1] = strings.TrimSpace(params[1])
-
-	if col.precision, err = strconv.Atoi(params[0]); err != nil {
-		return nil, fmt.Errorf("'%s' is not Decimal type: %s", t, err)
-	} else if col.precision < 1 {
-		return nil, errors.New("wrong precision of Decimal type")
-	}
 
-	if col.scale, err = strconv.Atoi(params[1]); err != nil {
+	if col.scale, err = strconv.Atoi(params[0]); err != nil {
 		return nil, fmt.Errorf("'%s' is not Decimal type: %s", t, err)
 	} else if col.scale < 0 || col.scale > col.precision {
 		return nil, errors.New("wrong scale of Decimal type")
@@ -47,10 +69,12 @@ func (col *Decimal) parse(t Type) (_ *Decimal, err error) {
 		col.nobits = 64
 	case col.precision <= 38:
 		col.nobits = 128
+	case col.precision <= 76:
+		col.nobits = 256
 	default:
 		return nil, errors.New("precision of Decimal exceeds max bound")
 	}
-
+	col.max = new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(col.precision)), nil)
 	return col, nil
 }
 
@@ -92,15 +116,29 @@ func (col *Decimal) ScanRow(dest interface{}, row int) error {
 }
 
 func (col *Decimal) Append(v interface{}) (nulls []uint8, err error) {
+	rows := len(col.values)
+	defer func() {
+		// a slice is appended entirely or not at all, so the columns of the batch keep the same length
+		if err != nil {
+			col.values = col.values[:rows]
+		}
+	}()
 	switch v := v.(type) {
 	case []decimal.Decimal:
-		col.values, nulls = append(col.values, v...), make([]uint8, len(v))
+		nulls = make([]uint8, len(v))
+		for _, v := range v {
+			if err := col.append(v); err != nil {
+				return nil, err
+			}
+		}
 	case []*decimal.Decimal:
 		nulls = make([]uint8, len(v))
 		for i, v := range v {
 			switch {
 			case v != nil:
-				col.values = append(col.values, *v)
+				if err := col.append(*v); err != nil {
+					return nil, err
+				}
 			default:
 				col.values, nulls[i] = append(col.values, decimal.New(0, 0)), 1
 			}
@@ -132,7 +170,20 @@ func (col *Decimal) AppendRow(v interface{}) error {
 			From: fmt.Sprintf("%T", v),
 		}
 	}
-	col.values = append(col.values, value)
+	return col.append(value)
+}
+
+// append checks that the value scaled to the scale of the column has at most precision digits,
+// the values which do not fit are rejected instead of being truncated on Encode.
+func (col *Decimal) append(v decimal.Decimal) error {
+	scaled := decimal.NewFromBigInt(v.Coefficient(), v.Exponent()+int32(col.scale)).BigInt()
+	if scaled.CmpAbs(col.max) >= 0 {
+		return &Error{
+			ColumnType: string(col.chType),
+			Err:        fmt.Errorf("value %s overflows %s", v, col.chType),
+		}
+	}
+	col.values = append(col.values, v)
 	return nil
 }
 
@@ -154,16 +205,14 @@ func (col *Decimal) Decode(decoder *binary.Decoder, rows int) error {
 		for _, v := range base {
 			col.values = append(col.values, decimal.New(int64(v), int32(-col.scale)))
 		}
-	case 128:
-		scratch := make([]byte, rows*16)
+	case 128, 256:
+		size := col.nobits / 8
+		scratch := make([]byte, rows*size)
 		if err := decoder.Raw(scratch); err != nil {
 			return err
 		}
 		for i := 0; i < rows; i++ {
-			bi, err := decimal128ToBigInt(scratch[i*16 : (i+1)*16])
-			if err != nil {
-				return err
-			}
+			bi := rawToBigInt(scratch[i*size:(i+1)*size], true)
 			col.values = append(col.values, decimal.NewFromBigInt(bi, int32(-col.scale)))
 		}
 	default:
@@ -200,8 +249,9 @@ func (col *Decimal) Encode(encoder *binary.Encoder) error {
 			base = append(base, part)
 		}
 		return base.Encode(encoder)
-	case 128:
-		scratch := make([]byte, col.Rows()*16)
+	case 128, 256:
+		size := col.nobits / 8
+		scratch := make([]byte, col.Rows()*size)
 		for i, v := range col.values {
 			var bi *big.Int
 			switch {
@@ -210,7 +260,7 @@ func (col *Decimal) Encode(encoder *binary.Encoder) error {
 			default:
 				bi = v.BigInt()
 			}
-			copyBigIntToRawDecimal128(scratch[i*16:(i+1)*16], bi)
+			copyBigIntToRaw(scratch[i*size:(i+1)*size], bi)
 		}
 		return encoder.Raw(scratch)
 	}
@@ -227,38 +277,6 @@ func (col *Decimal) Precision() int64 {
 
 var _ Interface = (*Decimal)(nil)
 
-func decimal128ToBigInt(v []byte) (*big.Int, error) {
-	if len(v) != 16 {
-		return nil, errors.New("expected 16 bytes")
-	}
-	// LittleEndian to BigEndian
-	endianSwap(v, false)
-	var lt = new(big.Int)
-	if len(v) > 0 && v[0]&0x80 != 0 {
-		// [0] ^ will +1
-		for i := 0; i < len(v); i++ {
-			v[i] = ^v[i]
-		}
-		lt.SetBytes(v)
-		// neg ^ will -1
-		lt.Not(lt)
-	} else {
-		lt.SetBytes(v)
-	}
-	return lt, nil
-}
-
-func copyBigIntToRawDecimal128(dest []byte, v *big.Int) {
-	var sign int
-	if v.Sign() < 0 {
-		v.Not(v).FillBytes(dest)
-		sign = -1
-	} else {
-		v.FillBytes(dest)
-	}
-	endianSwap(dest, sign < 0)
-}
-
 func endianSwap(src []byte, not bool) {
 	for i := 0; i < len(src)/2; i++ {
 		if not {
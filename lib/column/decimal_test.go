@@ -0,0 +1,114 @@
+package column
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ClickHouse/clickhouse-go/v2/lib/binary"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecimalParse(t *testing.T) {
+	for _, asset := range []struct {
+		chType    Type
+		precision int64
+		scale     int64
+		nobits    int
+	}{
+		{chType: "Decimal(9, 3)", precision: 9, scale: 3, nobits: 32},
+		{chType: "Decimal(18,5)", precision: 18, scale: 5, nobits: 64},
+		{chType: "Decimal(38, 10)", precision: 38, scale: 10, nobits: 128},
+		{chType: "Decimal(76, 20)", precision: 76, scale: 20, nobits: 256},
+		{chType: "Decimal32(4)", precision: 9, scale: 4, nobits: 32},
+		{chType: "Decimal64(6)", precision: 18, scale: 6, nobits: 64},
+		{chType: "Decimal128(12)", precision: 38, scale: 12, nobits: 128},
+		{chType: "Decimal256(40)", precision: 76, scale: 40, nobits: 256},
+	} {
+		col, err := asset.chType.Column()
+		if assert.NoError(t, err) {
+			if dec, ok := col.(*Decimal); assert.True(t, ok, asset.chType) {
+				assert.Equal(t, asset.precision, dec.Precision(), asset.chType)
+				assert.Equal(t, asset.scale, dec.Scale(), asset.chType)
+				assert.Equal(t, asset.nobits, dec.nobits, asset.chType)
+				assert.Equal(t, asset.chType, dec.Type())
+			}
+		}
+	}
+	for _, chType := range []Type{"Decimal(77, 2)", "Decimal(10, 11)", "Decimal32(10)", "Decimal512(2)", "Decimal(10)"} {
+		_, err := chType.Column()
+		assert.Error(t, err, chType)
+	}
+}
+
+func TestDecimal256(t *testing.T) {
+	var (
+		buffer  bytes.Buffer
+		decoder = binary.NewDecoder(&buffer)
+		encoder = binary.NewEncoder(&buffer)
+		values  = []decimal.Decimal{
+			decimal.RequireFromString("-123456789012345678901234567890123456789012345678901234.0123456789"),
+			decimal.RequireFromString("42.5"),
+			decimal.New(0, 0),
+		}
+	)
+	col, err := Type("Decimal256(10)").Column()
+	if !assert.NoError(t, err) {
+		return
+	}
+	for _, v := range values {
+		if !assert.NoError(t, col.AppendRow(v)) {
+			return
+		}
+	}
+	if assert.NoError(t, col.Encode(encoder)) {
+		col2, err := Type("Decimal256(10)").Column()
+		if !assert.NoError(t, err) {
+			return
+		}
+		if assert.NoError(t, col2.Decode(decoder, len(values))) {
+			for i, expected := range values {
+				var v decimal.Decimal
+				if assert.NoError(t, col2.ScanRow(&v, i)) {
+					assert.True(t, expected.Equal(v), "expected %s got %s", expected, v)
+				}
+			}
+		}
+	}
+}
+
+func TestDecimalOverflow(t *testing.T) {
+	for _, asset := range []struct {
+		chType Type
+		fits   string
+		value  string
+	}{
+		{chType: "Decimal(9, 3)", fits: "999999.999", value: "1000000"},
+		{chType: "Decimal(18, 5)", fits: "-9999999999999.99999", value: "-10000000000000"},
+		{chType: "Decimal128(2)", fits: "999999999999999999999999999999999999.99", value: "1000000000000000000000000000000000000"},
+		{chType: "Decimal256(0)", fits: "-9999999999999999999999999999999999999999999999999999999999999999999999999999", value: "99999999999999999999999999999999999999999999999999999999999999999999999999999"},
+	} {
+		col, err := asset.chType.Column()
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.NoError(t, col.AppendRow(decimal.RequireFromString(asset.fits)), asset.chType)
+		if err := col.AppendRow(decimal.RequireFromString(asset.value)); assert.Error(t, err, asset.chType) {
+			_, ok := err.(*Error)
+			assert.True(t, ok)
+		}
+		var (
+			fits  = decimal.RequireFromString(asset.fits)
+			value = decimal.RequireFromString(asset.value)
+		)
+		_, err = col.Append([]decimal.Decimal{fits, value})
+		assert.Error(t, err, asset.chType)
+		_, err = col.Append([]*decimal.Decimal{&fits, nil, &value})
+		assert.Error(t, err, asset.chType)
+		// the rejected values and the rest of their slices are not appended, the column can be encoded
+		var buffer bytes.Buffer
+		if assert.Equal(t, 1, col.Rows()) {
+			assert.NoError(t, col.Encode(binary.NewEncoder(&buffer)))
+		}
+	}
+}
@@ -0,0 +1,300 @@
+package column
+
+import (
+	"fmt"
+	"math/big"
+	"net"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ClickHouse/clickhouse-go/v2/lib/binary"
+	"github.com/ClickHouse/clickhouse-go/v2/lib/geo"
+	"github.com/google/uuid"
+)
+
+const (
+	dynamicSerializationV1 = 1
+	dynamicSerializationV2 = 2
+	// dynamicSharedVariant holds the values of the types above the max_types limit.
+	// Its values are kept as the binary encoded type followed by the binary encoded value.
+	dynamicSharedVariant   = Type("SharedVariant")
+	dynamicDefaultMaxTypes = 32
+)
+
+// Dynamic stores values of any type, the type of every row is sent along with the column.
+// On the wire it is a Variant of the used types and of the SharedVariant.
+// https://clickhouse.com/docs/en/sql-reference/data-types/dynamic
+type Dynamic struct {
+	chType   Type
+	maxTypes int
+	variant  Variant
+}
+
+func (col *Dynamic) parse(t Type) (_ Interface, err error) {
+	col.chType, col.maxTypes = t, dynamicDefaultMaxTypes
+	if params := t.params(); len(params) != 0 {
+		// Dynamic(max_types=N)
+		param := strings.SplitN(params, "=", 2)
+		if len(param) != 2 || strings.TrimSpace(param[0]) != "max_types" {
+			return &UnsupportedColumnType{
+				t: t,
+			}, nil
+		}
+		if col.maxTypes, err = strconv.Atoi(strings.TrimSpace(param[1])); err != nil {
+			return nil, err
+		}
+	}
+	if err := col.reset(nil); err != nil {
+		return nil, err
+	}
+	return col, nil
+}
+
+func (col *Dynamic) Type() Type {
+	return col.chType
+}
+
+func (col *Dynamic) ScanType() reflect.Type {
+	return scanTypeAny
+}
+
+func (col *Dynamic) Rows() int {
+	return col.variant.Rows()
+}
+
+// RowType returns the type of the row, an empty type for NULL.
+func (col *Dynamic) RowType(row int) Type {
+	return col.variant.RowType(row)
+}
+
+func (col *Dynamic) Row(i int, ptr bool) interface{} {
+	return col.variant.Row(i, ptr)
+}
+
+func (col *Dynamic) ScanRow(dest interface{}, row int) error {
+	return col.variant.ScanRow(dest, row)
+}
+
+func (col *Dynamic) Append(v interface{}) (nulls []uint8, err error) {
+	value := reflect.ValueOf(v)
+	if value.Kind() != reflect.Slice {
+		return nil, &ColumnConverterError{
+			Op:   "Append",
+			To:   string(col.chType),
+			From: fmt.Sprintf("%T", v),
+			Hint: "value must be a slice",
+		}
+	}
+	nulls = make([]uint8, value.Len())
+	for i := 0; i < value.Len(); i++ {
+		if err := col.AppendRow(value.Index(i).Interface()); err != nil {
+			return nil, err
+		}
+	}
+	return
+}
+
+func (col *Dynamic) AppendRow(v interface{}) error {
+	value, ok := v.(VariantValue)
+	if !ok {
+		rv := reflect.ValueOf(v)
+		for rv.Kind() == reflect.Ptr && !rv.IsNil() {
+			rv = rv.Elem()
+		}
+		if !rv.IsValid() || rv.Kind() == reflect.Ptr {
+			return col.variant.appendNull()
+		}
+		t, err := inferType(rv)
+		if err != nil {
+			return &ColumnConverterError{
+				Op:   "AppendRow",
+				To:   string(col.chType),
+				From: fmt.Sprintf("%T", v),
+				Hint: "use column.VariantValue to set the type explicitly",
+			}
+		}
+		value = VariantValue{
+			Type:  t,
+			Value: rv.Interface(),
+		}
+	}
+	if value.Value == nil {
+		return col.variant.appendNull()
+	}
+	idx := col.variant.variant(value.Type)
+	if idx == -1 {
+		if len(col.variant.types)-1 >= col.maxTypes {
+			return &Error{
+				ColumnType: string(col.chType),
+				Err:        fmt.Errorf("too many types, the limit is %d", col.maxTypes),
+			}
+		}
+		c, err := value.Type.Column()
+		if err != nil {
+			return err
+		}
+		if _, ok := c.(*UnsupportedColumnType); ok {
+			return &Error{
+				ColumnType: string(col.chType),
+				Err:        fmt.Errorf("unsupported type %s", value.Type),
+			}
+		}
+		idx = col.variant.addVariant(value.Type, c)
+	}
+	return col.variant.append(idx, value.Value)
+}
+
+func (col *Dynamic) Decode(decoder *binary.Decoder, rows int) error {
+	return col.variant.Decode(decoder, rows)
+}
+
+func (col *Dynamic) Encode(encoder *binary.Encoder) error {
+	return col.variant.Encode(encoder)
+}
+
+func (col *Dynamic) ReadStatePrefix(decoder *binary.Decoder) error {
+	version, err := decoder.UInt64()
+	if err != nil {
+		return err
+	}
+	switch version {
+	case dynamicSerializationV1:
+		maxTypes, err := decoder.Uvarint()
+		if err != nil {
+			return err
+		}
+		col.maxTypes = int(maxTypes)
+	case dynamicSerializationV2:
+	default:
+		return &Error{
+			ColumnType: string(col.chType),
+			Err:        fmt.Errorf("unsupported serialization version %d", version),
+		}
+	}
+	numTypes, err := decoder.Uvarint()
+	if err != nil {
+		return err
+	}
+	types := make([]Type, 0, numTypes)
+	for i := 0; i < int(numTypes); i++ {
+		t, err := decoder.String()
+		if err != nil {
+			return err
+		}
+		types = append(types, Type(t))
+	}
+	if err := col.reset(types); err != nil {
+		return err
+	}
+	return col.variant.ReadStatePrefix(decoder)
+}
+
+func (col *Dynamic) WriteStatePrefix(encoder *binary.Encoder) error {
+	if err := encoder.UInt64(dynamicSerializationV1); err != nil {
+		return err
+	}
+	if err := encoder.Uvarint(uint64(col.maxTypes)); err != nil {
+		return err
+	}
+	if err := encoder.Uvarint(uint64(len(col.variant.types) - 1)); err != nil {
+		return err
+	}
+	for _, t := range col.variant.types {
+		if t != dynamicSharedVariant {
+			if err := encoder.String(string(t)); err != nil {
+				return err
+			}
+		}
+	}
+	return col.variant.WriteStatePrefix(encoder)
+}
+
+func (col *Dynamic) reset(types []Type) error {
+	col.variant = Variant{
+		chType: col.chType,
+	}
+	col.variant.addVariant(dynamicSharedVariant, &String{})
+	for _, t := range types {
+		c, err := t.Column()
+		if err != nil {
+			return err
+		}
+		col.variant.addVariant(t, c)
+	}
+	return nil
+}
+
+// inferType returns the ClickHouse type of a Go value.
+func inferType(v reflect.Value) (Type, error) {
+	switch v := v.Interface().(type) {
+	case time.Time:
+		return "DateTime64(9)", nil
+	case uuid.UUID:
+		return "UUID", nil
+	case net.IP:
+		if v.To4() != nil {
+			return "IPv4", nil
+		}
+		return "IPv6", nil
+	case big.Int:
+		return "Int256", nil
+	case geo.Point:
+		return "Point", nil
+	case geo.Ring:
+		return "Ring", nil
+	case geo.Polygon:
+		return "Polygon", nil
+	case geo.MultiPolygon:
+		return "MultiPolygon", nil
+	}
+	switch v.Kind() {
+	case reflect.Bool:
+		return "Bool", nil
+	case reflect.String:
+		return "String", nil
+	case reflect.Int, reflect.Int64:
+		return "Int64", nil
+	case reflect.Int8:
+		return "Int8", nil
+	case reflect.Int16:
+		return "Int16", nil
+	case reflect.Int32:
+		return "Int32", nil
+	case reflect.Uint, reflect.Uint64:
+		return "UInt64", nil
+	case reflect.Uint8:
+		return "UInt8", nil
+	case reflect.Uint16:
+		return "UInt16", nil
+	case reflect.Uint32:
+		return "UInt32", nil
+	case reflect.Float32:
+		return "Float32", nil
+	case reflect.Float64:
+		return "Float64", nil
+	case reflect.Slice:
+		elem, err := inferType(reflect.Zero(v.Type().Elem()))
+		if err != nil {
+			return "", err
+		}
+		return Type(fmt.Sprintf("Array(%s)", elem)), nil
+	case reflect.Map:
+		key, err := inferType(reflect.Zero(v.Type().Key()))
+		if err != nil {
+			return "", err
+		}
+		value, err := inferType(reflect.Zero(v.Type().Elem()))
+		if err != nil {
+			return "", err
+		}
+		return Type(fmt.Sprintf("Map(%s, %s)", key, value)), nil
+	}
+	return "", fmt.Errorf("unsupported type %s", v.Type())
+}
+
+var (
+	_ Interface           = (*Dynamic)(nil)
+	_ CustomSerialization = (*Dynamic)(nil)
+)
@@ -0,0 +1,37 @@
+package column
+
+import (
+	"reflect"
+)
+
+// geoArray returns the column of the Ring, Polygon and MultiPolygon types. They are stored as Array(Point),
+// Array(Array(Point)) and Array(Array(Array(Point))) and read into the slices of the geo package.
+func geoArray(t Type) (Interface, error) {
+	var (
+		base      Type
+		scanTypes []reflect.Type
+	)
+	switch t {
+	case "Ring":
+		base, scanTypes = "Array(Point)", []reflect.Type{scanTypeRing}
+	case "Polygon":
+		base, scanTypes = "Array(Array(Point))", []reflect.Type{scanTypePolygon, scanTypeRing}
+	case "MultiPolygon":
+		base, scanTypes = "Array(Array(Array(Point)))", []reflect.Type{scanTypeMultiPolygon, scanTypePolygon, scanTypeRing}
+	default:
+		return &UnsupportedColumnType{
+			t: t,
+		}, nil
+	}
+	col, err := (&Array{}).parse(base)
+	if err != nil {
+		return nil, err
+	}
+	array := col.(*Array)
+	// the offsets of every level make the geo type instead of [][]geo.Point
+	for i, offset := range array.offsets {
+		offset.scanType = scanTypes[i]
+	}
+	array.chType, array.scanType = t, scanTypes[0]
+	return array, nil
+}
@@ -0,0 +1,131 @@
+package column
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/ClickHouse/clickhouse-go/v2/lib/binary"
+	"github.com/ClickHouse/clickhouse-go/v2/lib/geo"
+)
+
+// Point is an alias of Tuple(Float64, Float64).
+type Point struct {
+	x Float64
+	y Float64
+}
+
+func (col *Point) Type() Type {
+	return "Point"
+}
+
+func (col *Point) ScanType() reflect.Type {
+	return scanTypePoint
+}
+
+func (col *Point) Rows() int {
+	return len(col.x)
+}
+
+func (col *Point) Row(i int, ptr bool) interface{} {
+	value := col.row(i)
+	if ptr {
+		return &value
+	}
+	return value
+}
+
+func (col *Point) ScanRow(dest interface{}, row int) error {
+	switch d := dest.(type) {
+	case *geo.Point:
+		*d = col.row(row)
+	case **geo.Point:
+		*d = new(geo.Point)
+		**d = col.row(row)
+	default:
+		return &ColumnConverterError{
+			Op:   "ScanRow",
+			To:   fmt.Sprintf("%T", dest),
+			From: "Point",
+			Hint: fmt.Sprintf("try using *%s", scanTypePoint),
+		}
+	}
+	return nil
+}
+
+func (col *Point) Append(v interface{}) (nulls []uint8, err error) {
+	switch v := v.(type) {
+	case []geo.Point:
+		nulls = make([]uint8, len(v))
+		for _, v := range v {
+			col.append(v)
+		}
+	case []*geo.Point:
+		nulls = make([]uint8, len(v))
+		for i, v := range v {
+			switch {
+			case v != nil:
+				col.append(*v)
+			default:
+				col.append(geo.Point{})
+				nulls[i] = 1
+			}
+		}
+	default:
+		return nil, &ColumnConverterError{
+			Op:   "Append",
+			To:   "Point",
+			From: fmt.Sprintf("%T", v),
+		}
+	}
+	return
+}
+
+func (col *Point) AppendRow(v interface{}) error {
+	switch v := v.(type) {
+	case geo.Point:
+		col.append(v)
+	case *geo.Point:
+		switch {
+		case v != nil:
+			col.append(*v)
+		default:
+			col.append(geo.Point{})
+		}
+	case nil:
+		col.append(geo.Point{})
+	default:
+		return &ColumnConverterError{
+			Op:   "AppendRow",
+			To:   "Point",
+			From: fmt.Sprintf("%T", v),
+		}
+	}
+	return nil
+}
+
+func (col *Point) Decode(decoder *binary.Decoder, rows int) error {
+	if err := col.x.Decode(decoder, rows); err != nil {
+		return err
+	}
+	return col.y.Decode(decoder, rows)
+}
+
+func (col *Point) Encode(encoder *binary.Encoder) error {
+	if err := col.x.Encode(encoder); err != nil {
+		return err
+	}
+	return col.y.Encode(encoder)
+}
+
+func (col *Point) append(v geo.Point) {
+	col.x, col.y = append(col.x, v.X), append(col.y, v.Y)
+}
+
+func (col *Point) row(i int) geo.Point {
+	return geo.Point{
+		X: col.x[i],
+		Y: col.y[i],
+	}
+}
+
+var _ Interface = (*Point)(nil)
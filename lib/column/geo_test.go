@@ -0,0 +1,80 @@
+package column
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ClickHouse/clickhouse-go/v2/lib/binary"
+	"github.com/ClickHouse/clickhouse-go/v2/lib/geo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGeo(t *testing.T) {
+	var (
+		ring = geo.Ring{
+			{X: 0, Y: 0},
+			{X: 10, Y: 0},
+			{X: 10, Y: 10},
+			{X: 0, Y: 10},
+		}
+		hole = geo.Ring{
+			{X: 2, Y: 2},
+			{X: 3, Y: 2},
+			{X: 3, Y: 3},
+		}
+	)
+	for _, asset := range []struct {
+		chType Type
+		values []interface{}
+	}{
+		{chType: "Point", values: []interface{}{geo.Point{X: 1, Y: 2}, geo.Point{X: -3.5, Y: 4.25}}},
+		{chType: "Ring", values: []interface{}{ring, geo.Ring{}, hole}},
+		{chType: "Polygon", values: []interface{}{geo.Polygon{ring, hole}, geo.Polygon{}, geo.Polygon{hole}}},
+		{chType: "MultiPolygon", values: []interface{}{geo.MultiPolygon{{ring, hole}, {hole}}, geo.MultiPolygon{}}},
+	} {
+		var (
+			buffer  bytes.Buffer
+			decoder = binary.NewDecoder(&buffer)
+			encoder = binary.NewEncoder(&buffer)
+		)
+		col, err := asset.chType.Column()
+		if !assert.NoError(t, err) {
+			return
+		}
+		for _, v := range asset.values {
+			if !assert.NoError(t, col.AppendRow(v)) {
+				return
+			}
+		}
+		if assert.NoError(t, col.Encode(encoder)) {
+			col2, err := asset.chType.Column()
+			if !assert.NoError(t, err) {
+				return
+			}
+			if assert.NoError(t, col2.Decode(decoder, len(asset.values))) {
+				for i, expected := range asset.values {
+					assert.Equal(t, expected, col2.Row(i, false), "%s: row %d", asset.chType, i)
+				}
+			}
+		}
+	}
+}
+
+func TestGeoArrayScan(t *testing.T) {
+	col, err := Type("Polygon").Column()
+	if !assert.NoError(t, err) {
+		return
+	}
+	polygon := geo.Polygon{{{X: 1, Y: 1}, {X: 2, Y: 1}, {X: 2, Y: 2}}}
+	if assert.NoError(t, col.AppendRow(&polygon)) {
+		assert.Equal(t, Type("Polygon"), col.Type())
+		var v geo.Polygon
+		if assert.NoError(t, col.ScanRow(&v, 0)) {
+			assert.Equal(t, polygon, v)
+		}
+		var ring geo.Ring
+		assert.Error(t, col.ScanRow(&ring, 0))
+	}
+	// a Polygon is not a [][]geo.Point
+	assert.Error(t, col.AppendRow([][]geo.Point{}))
+}
@@ -0,0 +1,786 @@
+package column
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ClickHouse/clickhouse-go/v2/lib/binary"
+)
+
+const (
+	jsonSerializationV1        = 0
+	jsonSerializationString    = 1
+	jsonSerializationV2        = 2
+	jsonDefaultMaxDynamicPaths = 1024
+	jsonDefaultMaxDynamicTypes = 32
+	// jsonNestedObjectType is the type inferred for the objects in arrays.
+	jsonNestedObjectType = Type("JSON(max_dynamic_types=16, max_dynamic_paths=256)")
+)
+
+// JSON stores the paths of the objects as separate subcolumns: the typed paths declared by the type,
+// up to max_dynamic_paths Dynamic paths and the shared data holding the other paths of every row.
+// The paths of the inserted rows and their types are inferred from the values.
+// https://clickhouse.com/docs/en/sql-reference/data-types/newjson
+type JSON struct {
+	chType          Type
+	maxDynamicPaths int
+	maxDynamicTypes int
+	typedPaths      []string
+	typedTypes      map[string]Type
+	skipPaths       []string
+	skipRegexps     []*regexp.Regexp
+	rows            []map[string]interface{}
+
+	// the subcolumns are read from the state prefix or built from the rows before encoding
+	prepared      bool
+	serialization uint64
+	typed         []Interface
+	dynamicPaths  []string
+	dynamic       []*Dynamic
+	sharedOffsets UInt64
+	sharedPaths   String
+	sharedValues  String
+}
+
+func (col *JSON) parse(t Type) (_ Interface, err error) {
+	col.chType, col.maxDynamicPaths, col.maxDynamicTypes = t, jsonDefaultMaxDynamicPaths, jsonDefaultMaxDynamicTypes
+	if t != "JSON" && !strings.HasPrefix(string(t), "JSON(") {
+		return &UnsupportedColumnType{
+			t: t,
+		}, nil
+	}
+	col.typedTypes = make(map[string]Type)
+	for _, param := range splitTypeParams(t.params()) {
+		switch {
+		case strings.HasPrefix(param, "SKIP REGEXP "):
+			expr := strings.TrimSpace(strings.TrimPrefix(param, "SKIP REGEXP "))
+			re, err := regexp.Compile(strings.Trim(expr, "'"))
+			if err != nil {
+				return nil, err
+			}
+			col.skipRegexps = append(col.skipRegexps, re)
+		case strings.HasPrefix(param, "SKIP "):
+			col.skipPaths = append(col.skipPaths, unquoteJSONPath(strings.TrimSpace(strings.TrimPrefix(param, "SKIP "))))
+		case strings.HasPrefix(param, "max_dynamic_paths"), strings.HasPrefix(param, "max_dynamic_types"):
+			setting := strings.SplitN(param, "=", 2)
+			if len(setting) != 2 {
+				return nil, fmt.Errorf("invalid JSON type parameter %q", param)
+			}
+			value, err := strconv.Atoi(strings.TrimSpace(setting[1]))
+			if err != nil {
+				return nil, err
+			}
+			if strings.TrimSpace(setting[0]) == "max_dynamic_paths" {
+				col.maxDynamicPaths = value
+			} else {
+				col.maxDynamicTypes = value
+			}
+		default:
+			path, pathType := splitJSONTypedPath(param)
+			if len(pathType) == 0 {
+				return nil, fmt.Errorf("invalid JSON type parameter %q", param)
+			}
+			col.typedPaths, col.typedTypes[path] = append(col.typedPaths, path), Type(pathType)
+		}
+	}
+	sort.Strings(col.typedPaths)
+	return col, nil
+}
+
+func (col *JSON) Type() Type {
+	return col.chType
+}
+
+func (col *JSON) ScanType() reflect.Type {
+	return scanTypeJSON
+}
+
+func (col *JSON) Rows() int {
+	return len(col.rows)
+}
+
+func (col *JSON) Row(i int, ptr bool) interface{} {
+	value := col.rows[i]
+	if ptr {
+		return &value
+	}
+	return value
+}
+
+func (col *JSON) ScanRow(dest interface{}, row int) error {
+	switch d := dest.(type) {
+	case *map[string]interface{}:
+		*d = col.rows[row]
+	case *string:
+		v, err := json.Marshal(col.rows[row])
+		if err != nil {
+			return err
+		}
+		*d = string(v)
+	default:
+		if isStructPtr(dest) {
+			return scanJSON(col.rows[row], dest)
+		}
+		return &ColumnConverterError{
+			Op:   "ScanRow",
+			To:   fmt.Sprintf("%T", dest),
+			From: string(col.chType),
+			Hint: fmt.Sprintf("try using *%s, *string or a pointer to a struct", scanTypeJSON),
+		}
+	}
+	return nil
+}
+
+func (col *JSON) Append(v interface{}) (nulls []uint8, err error) {
+	value := reflect.ValueOf(v)
+	if value.Kind() != reflect.Slice {
+		return nil, &ColumnConverterError{
+			Op:   "Append",
+			To:   string(col.chType),
+			From: fmt.Sprintf("%T", v),
+			Hint: "value must be a slice",
+		}
+	}
+	nulls = make([]uint8, value.Len())
+	for i := 0; i < value.Len(); i++ {
+		if err := col.AppendRow(value.Index(i).Interface()); err != nil {
+			return nil, err
+		}
+	}
+	return
+}
+
+func (col *JSON) AppendRow(v interface{}) error {
+	row, err := jsonObject(col.chType, v)
+	if err != nil {
+		return err
+	}
+	col.rows, col.prepared = append(col.rows, row), false
+	return nil
+}
+
+func (col *JSON) Decode(decoder *binary.Decoder, rows int) error {
+	if col.serialization == jsonSerializationString {
+		var values String
+		if err := values.Decode(decoder, rows); err != nil {
+			return err
+		}
+		for _, v := range values {
+			var row map[string]interface{}
+			if err := json.Unmarshal([]byte(v), &row); err != nil {
+				return &Error{
+					ColumnType: string(col.chType),
+					Err:        err,
+				}
+			}
+			col.rows = append(col.rows, row)
+		}
+		return nil
+	}
+	for _, c := range col.typed {
+		if err := c.Decode(decoder, rows); err != nil {
+			return err
+		}
+	}
+	for _, c := range col.dynamic {
+		if err := c.Decode(decoder, rows); err != nil {
+			return err
+		}
+	}
+	var (
+		offsets       UInt64
+		paths, values String
+	)
+	if err := offsets.Decode(decoder, rows); err != nil {
+		return err
+	}
+	var shared int
+	if rows != 0 {
+		shared = int(offsets[rows-1])
+	}
+	if err := paths.Decode(decoder, shared); err != nil {
+		return err
+	}
+	if err := values.Decode(decoder, shared); err != nil {
+		return err
+	}
+	var (
+		start int
+		base  = len(col.rows)
+	)
+	for i := 0; i < rows; i++ {
+		row := make(map[string]interface{})
+		for j, path := range col.typedPaths {
+			row[path] = col.typed[j].Row(base+i, false)
+		}
+		for j, path := range col.dynamicPaths {
+			switch c := col.dynamic[j]; c.RowType(base + i) {
+			case "":
+			case dynamicSharedVariant:
+				v, err := decodeBinaryValue(c.Row(base+i, false).(string))
+				if err != nil {
+					return &Error{
+						ColumnType: string(col.chType),
+						Err:        fmt.Errorf("path %s: %w", path, err),
+					}
+				}
+				row[path] = v
+			default:
+				row[path] = c.Row(base+i, false)
+			}
+		}
+		for ; start < int(offsets[i]); start++ {
+			v, err := decodeBinaryValue(values[start])
+			if err != nil {
+				return &Error{
+					ColumnType: string(col.chType),
+					Err:        fmt.Errorf("path %s: %w", paths[start], err),
+				}
+			}
+			row[paths[start]] = v
+		}
+		col.rows = append(col.rows, unflattenJSON(row))
+	}
+	return nil
+}
+
+func (col *JSON) Encode(encoder *binary.Encoder) error {
+	if err := col.prepare(); err != nil {
+		return err
+	}
+	for _, c := range col.typed {
+		if err := c.Encode(encoder); err != nil {
+			return err
+		}
+	}
+	for _, c := range col.dynamic {
+		if err := c.Encode(encoder); err != nil {
+			return err
+		}
+	}
+	if err := col.sharedOffsets.Encode(encoder); err != nil {
+		return err
+	}
+	if err := col.sharedPaths.Encode(encoder); err != nil {
+		return err
+	}
+	return col.sharedValues.Encode(encoder)
+}
+
+func (col *JSON) ReadStatePrefix(decoder *binary.Decoder) error {
+	version, err := decoder.UInt64()
+	if err != nil {
+		return err
+	}
+	switch version {
+	case jsonSerializationV1:
+		maxPaths, err := decoder.Uvarint()
+		if err != nil {
+			return err
+		}
+		col.maxDynamicPaths = int(maxPaths)
+	case jsonSerializationV2:
+	case jsonSerializationString:
+		col.serialization = version
+		return nil
+	default:
+		return &Error{
+			ColumnType: string(col.chType),
+			Err:        fmt.Errorf("unsupported serialization version %d", version),
+		}
+	}
+	col.serialization = version
+	numPaths, err := decoder.Uvarint()
+	if err != nil {
+		return err
+	}
+	col.dynamicPaths = make([]string, 0, numPaths)
+	for i := 0; i < int(numPaths); i++ {
+		path, err := decoder.String()
+		if err != nil {
+			return err
+		}
+		col.dynamicPaths = append(col.dynamicPaths, path)
+	}
+	if err := col.reset(); err != nil {
+		return err
+	}
+	for _, c := range col.typed {
+		if serialize, ok := c.(CustomSerialization); ok {
+			if err := serialize.ReadStatePrefix(decoder); err != nil {
+				return err
+			}
+		}
+	}
+	for _, c := range col.dynamic {
+		if err := c.ReadStatePrefix(decoder); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (col *JSON) WriteStatePrefix(encoder *binary.Encoder) error {
+	if err := col.prepare(); err != nil {
+		return err
+	}
+	if err := encoder.UInt64(jsonSerializationV1); err != nil {
+		return err
+	}
+	if err := encoder.Uvarint(uint64(col.maxDynamicPaths)); err != nil {
+		return err
+	}
+	if err := encoder.Uvarint(uint64(len(col.dynamicPaths))); err != nil {
+		return err
+	}
+	for _, path := range col.dynamicPaths {
+		if err := encoder.String(path); err != nil {
+			return err
+		}
+	}
+	for _, c := range col.typed {
+		if serialize, ok := c.(CustomSerialization); ok {
+			if err := serialize.WriteStatePrefix(encoder); err != nil {
+				return err
+			}
+		}
+	}
+	for _, c := range col.dynamic {
+		if err := c.WriteStatePrefix(encoder); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reset creates the empty subcolumns of the typed and of the dynamic paths.
+func (col *JSON) reset() error {
+	col.typed, col.dynamic = make([]Interface, 0, len(col.typedPaths)), make([]*Dynamic, 0, len(col.dynamicPaths))
+	col.sharedOffsets, col.sharedPaths, col.sharedValues = nil, nil, nil
+	for _, path := range col.typedPaths {
+		c, err := col.typedTypes[path].Column()
+		if err != nil {
+			return err
+		}
+		col.typed = append(col.typed, c)
+	}
+	for range col.dynamicPaths {
+		c, err := Type(fmt.Sprintf("Dynamic(max_types=%d)", col.maxDynamicTypes)).Column()
+		if err != nil {
+			return err
+		}
+		dynamic, ok := c.(*Dynamic)
+		if !ok {
+			return &Error{
+				ColumnType: string(col.chType),
+				Err:        fmt.Errorf("unsupported max_dynamic_types %d", col.maxDynamicTypes),
+			}
+		}
+		col.dynamic = append(col.dynamic, dynamic)
+	}
+	return nil
+}
+
+// prepare fills the subcolumns from the rows. The first max_dynamic_paths paths (in the sorted order)
+// are the dynamic paths, the other paths go to the shared data.
+func (col *JSON) prepare() error {
+	if col.prepared {
+		return nil
+	}
+	var (
+		rows  = make([]map[string]interface{}, 0, len(col.rows))
+		paths = make(map[string]interface{})
+	)
+	for _, row := range col.rows {
+		flat := make(map[string]interface{})
+		col.flatten("", row, flat)
+		for path := range flat {
+			if _, typed := col.typedTypes[path]; !typed {
+				paths[path] = nil
+			}
+		}
+		rows = append(rows, flat)
+	}
+	col.dynamicPaths = sortedKeys(paths)
+	if len(col.dynamicPaths) > col.maxDynamicPaths {
+		col.dynamicPaths = col.dynamicPaths[:col.maxDynamicPaths]
+	}
+	if err := col.reset(); err != nil {
+		return err
+	}
+	dynamicPaths := make(map[string]*Dynamic, len(col.dynamicPaths))
+	for i, path := range col.dynamicPaths {
+		dynamicPaths[path] = col.dynamic[i]
+	}
+	for _, row := range rows {
+		for i, path := range col.typedPaths {
+			if err := appendJSONValue(col.typed[i], row[path]); err != nil {
+				return &Error{
+					ColumnType: string(col.chType),
+					Err:        fmt.Errorf("path %s: %w", path, err),
+				}
+			}
+		}
+		for i, path := range col.dynamicPaths {
+			if err := appendDynamicJSONValue(col.dynamic[i], row[path]); err != nil {
+				return &Error{
+					ColumnType: string(col.chType),
+					Err:        fmt.Errorf("path %s: %w", path, err),
+				}
+			}
+		}
+		for _, path := range sortedKeys(row) {
+			if _, typed := col.typedTypes[path]; typed {
+				continue
+			}
+			if _, dynamic := dynamicPaths[path]; dynamic {
+				continue
+			}
+			var buffer bytes.Buffer
+			if err := encodeBinaryJSONValue(binary.NewEncoder(&buffer), row[path]); err != nil {
+				return &Error{
+					ColumnType: string(col.chType),
+					Err:        fmt.Errorf("path %s: %w", path, err),
+				}
+			}
+			col.sharedPaths, col.sharedValues = append(col.sharedPaths, path), append(col.sharedValues, buffer.String())
+		}
+		col.sharedOffsets = append(col.sharedOffsets, uint64(len(col.sharedPaths)))
+	}
+	col.prepared = true
+	return nil
+}
+
+// flatten collects the values of the object by their paths (the keys joined by dots).
+// The typed paths are not flattened further, NULL values and skipped paths are left out.
+func (col *JSON) flatten(prefix string, object map[string]interface{}, paths map[string]interface{}) {
+	for key, value := range object {
+		path := prefix + key
+		if col.skip(path) {
+			continue
+		}
+		if _, typed := col.typedTypes[path]; typed {
+			paths[path] = value
+			continue
+		}
+		switch v := value.(type) {
+		case nil:
+		case map[string]interface{}:
+			col.flatten(path+".", v, paths)
+		default:
+			paths[path] = v
+		}
+	}
+}
+
+func (col *JSON) skip(path string) bool {
+	for _, skip := range col.skipPaths {
+		if path == skip || strings.HasPrefix(path, skip+".") {
+			return true
+		}
+	}
+	for _, re := range col.skipRegexps {
+		if re.MatchString(path) {
+			return true
+		}
+	}
+	return false
+}
+
+// readBinaryValue reads an object in the RowBinary format: the number of paths and every path
+// followed by its value, the values of the not typed paths are preceded by their binary encoded type.
+func (col *JSON) readBinaryValue(decoder *binary.Decoder) (reflect.Value, error) {
+	numPaths, err := decoder.Uvarint()
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	row := make(map[string]interface{}, numPaths)
+	for i := 0; i < int(numPaths); i++ {
+		path, err := decoder.String()
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		t, typed := col.typedTypes[path]
+		if !typed {
+			if t, err = decodeBinaryType(decoder); err != nil {
+				return reflect.Value{}, err
+			}
+			if t == "Nothing" {
+				continue
+			}
+		}
+		c, err := t.Column()
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		value, err := readBinaryValue(decoder, c)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		row[path] = rowValue(value)
+	}
+	return reflect.ValueOf(unflattenJSON(row)), nil
+}
+
+// appendJSONValue appends the value of a typed path, a missing value is the default of the type.
+func appendJSONValue(col Interface, v interface{}) error {
+	if v == nil {
+		if _, ok := col.(*Array); ok {
+			return col.AppendRow(reflect.MakeSlice(col.ScanType(), 0, 0).Interface())
+		}
+		return col.AppendRow(nil)
+	}
+	value, err := convertJSONValue(v, col.ScanType())
+	if err != nil {
+		return err
+	}
+	return col.AppendRow(value.Interface())
+}
+
+func appendDynamicJSONValue(col *Dynamic, v interface{}) error {
+	if v == nil {
+		return col.AppendRow(nil)
+	}
+	t, err := jsonValueType(v)
+	if err != nil {
+		return err
+	}
+	value, err := convertJSON(v, t)
+	if err != nil {
+		return err
+	}
+	return col.AppendRow(VariantValue{
+		Type:  t,
+		Value: value,
+	})
+}
+
+// jsonValueType returns the type inferred for the JSON value, an empty type for NULL.
+// As in ClickHouse the scalars in arrays are Nullable and the objects in arrays are JSON.
+func jsonValueType(v interface{}) (Type, error) {
+	switch v := v.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return "String", nil
+	case bool:
+		return "Bool", nil
+	case json.Number:
+		if _, err := v.Int64(); err == nil {
+			return "Int64", nil
+		}
+		return "Float64", nil
+	case map[string]interface{}:
+		return jsonNestedObjectType, nil
+	case []interface{}:
+		var elem Type
+		for _, value := range v {
+			t, err := jsonValueType(value)
+			if err != nil {
+				return "", err
+			}
+			if elem, err = unifyJSONTypes(elem, t); err != nil {
+				return "", err
+			}
+		}
+		switch elem {
+		case "":
+			// the type of the elements of empty arrays is unknown
+			elem = "Nullable(String)"
+		case "String", "Bool", "Int64", "Float64":
+			elem = Type(fmt.Sprintf("Nullable(%s)", elem))
+		}
+		return Type(fmt.Sprintf("Array(%s)", elem)), nil
+	}
+	return "", fmt.Errorf("unexpected value %T", v)
+}
+
+func unifyJSONTypes(a, b Type) (Type, error) {
+	switch {
+	case len(a) == 0:
+		return b, nil
+	case len(b) == 0, a == b:
+		return a, nil
+	case a == "Int64" && b == "Float64", a == "Float64" && b == "Int64":
+		return "Float64", nil
+	}
+	for _, wrapper := range []string{"Array", "Nullable"} {
+		if strings.HasPrefix(string(a), wrapper+"(") && strings.HasPrefix(string(b), wrapper+"(") {
+			t, err := unifyJSONTypes(Type(a.params()), Type(b.params()))
+			if err != nil {
+				return "", err
+			}
+			return Type(fmt.Sprintf("%s(%s)", wrapper, t)), nil
+		}
+	}
+	return "", fmt.Errorf("mixed %s and %s values", a, b)
+}
+
+// convertJSON converts the JSON value to the Go value of the type inferred by jsonValueType.
+func convertJSON(v interface{}, t Type) (interface{}, error) {
+	value, err := convertJSONValue(v, jsonScanType(t))
+	if err != nil {
+		return nil, err
+	}
+	return value.Interface(), nil
+}
+
+func jsonScanType(t Type) reflect.Type {
+	switch {
+	case t == "String":
+		return scanTypeString
+	case t == "Bool":
+		return scanTypeBool
+	case t == "Int64":
+		return scanTypeInt64
+	case t == "Float64":
+		return scanTypeFloat64
+	case strings.HasPrefix(string(t), "Nullable("):
+		return reflect.PtrTo(jsonScanType(Type(t.params())))
+	case strings.HasPrefix(string(t), "Array("):
+		return reflect.SliceOf(jsonScanType(Type(t.params())))
+	}
+	return scanTypeJSON
+}
+
+// convertJSONValue converts the JSON value (as decoded with json.Number) to the scan type of a column.
+func convertJSONValue(v interface{}, t reflect.Type) (reflect.Value, error) {
+	if v == nil {
+		return reflect.Zero(t), nil
+	}
+	if t.Kind() == reflect.Ptr {
+		elem, err := convertJSONValue(v, t.Elem())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		ptr := reflect.New(t.Elem())
+		ptr.Elem().Set(elem)
+		return ptr, nil
+	}
+	if value := reflect.ValueOf(v); value.Type().AssignableTo(t) {
+		return value, nil
+	}
+	value := reflect.New(t).Elem()
+	switch v := v.(type) {
+	case json.Number:
+		switch t.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			n, err := strconv.ParseInt(v.String(), 10, 64)
+			if err != nil || value.OverflowInt(n) {
+				return reflect.Value{}, fmt.Errorf("cannot convert %s to %s", v, t)
+			}
+			value.SetInt(n)
+			return value, nil
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			n, err := strconv.ParseUint(v.String(), 10, 64)
+			if err != nil || value.OverflowUint(n) {
+				return reflect.Value{}, fmt.Errorf("cannot convert %s to %s", v, t)
+			}
+			value.SetUint(n)
+			return value, nil
+		case reflect.Float32, reflect.Float64:
+			n, err := strconv.ParseFloat(v.String(), 64)
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			value.SetFloat(n)
+			return value, nil
+		case reflect.String:
+			value.SetString(v.String())
+			return value, nil
+		}
+	case string:
+		if t == scanTypeTime {
+			for _, layout := range []string{time.RFC3339Nano, "2006-01-02 15:04:05.999999999", "2006-01-02"} {
+				if tv, err := time.Parse(layout, v); err == nil {
+					return reflect.ValueOf(tv), nil
+				}
+			}
+		}
+	case []interface{}:
+		if t.Kind() == reflect.Slice {
+			slice := reflect.MakeSlice(t, 0, len(v))
+			for _, elem := range v {
+				value, err := convertJSONValue(elem, t.Elem())
+				if err != nil {
+					return reflect.Value{}, err
+				}
+				slice = reflect.Append(slice, value)
+			}
+			return slice, nil
+		}
+	}
+	return reflect.Value{}, fmt.Errorf("cannot convert %T to %s", v, t)
+}
+
+// unflattenJSON nests the values by the dot separated keys of their paths.
+// A path which conflicts with the value of a shorter path is kept as is.
+func unflattenJSON(paths map[string]interface{}) map[string]interface{} {
+	object := make(map[string]interface{}, len(paths))
+next:
+	for _, path := range sortedKeys(paths) {
+		var (
+			keys   = strings.Split(path, ".")
+			parent = object
+		)
+		for _, key := range keys[:len(keys)-1] {
+			switch v := parent[key].(type) {
+			case nil:
+				if _, found := parent[key]; found {
+					object[path] = paths[path]
+					continue next
+				}
+				child := make(map[string]interface{})
+				parent[key], parent = child, child
+			case map[string]interface{}:
+				parent = v
+			default:
+				object[path] = paths[path]
+				continue next
+			}
+		}
+		parent[keys[len(keys)-1]] = paths[path]
+	}
+	return object
+}
+
+// splitJSONTypedPath splits the path and the type of a typed path, e.g. `a.b` UInt32.
+func splitJSONTypedPath(param string) (string, string) {
+	if strings.HasPrefix(param, "`") {
+		if end := strings.Index(param[1:], "`"); end != -1 {
+			return param[1 : end+1], strings.TrimSpace(param[end+2:])
+		}
+	}
+	if parts := strings.SplitN(param, " ", 2); len(parts) == 2 {
+		return parts[0], strings.TrimSpace(parts[1])
+	}
+	return param, ""
+}
+
+func unquoteJSONPath(path string) string {
+	if len(path) > 1 && strings.HasPrefix(path, "`") && strings.HasSuffix(path, "`") {
+		return path[1 : len(path)-1]
+	}
+	return path
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+var (
+	_ Interface           = (*JSON)(nil)
+	_ CustomSerialization = (*JSON)(nil)
+)
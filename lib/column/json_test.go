@@ -0,0 +1,283 @@
+package column
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/ClickHouse/clickhouse-go/v2/lib/binary"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestObject(t *testing.T) {
+	type user struct {
+		Name string   `json:"name"`
+		Tags []string `json:"tags"`
+	}
+	type event struct {
+		ID    int64   `json:"id"`
+		Score float64 `json:"score"`
+		User  user    `json:"user"`
+		Items []struct {
+			SKU string `json:"sku"`
+		} `json:"items"`
+	}
+	var (
+		buffer  bytes.Buffer
+		decoder = binary.NewDecoder(&buffer)
+		encoder = binary.NewEncoder(&buffer)
+	)
+	col, err := Type("Object('json')").Column()
+	if !assert.NoError(t, err) {
+		return
+	}
+	rows := []interface{}{
+		map[string]interface{}{"id": 1, "score": 1.5, "user": map[string]interface{}{"name": "A", "tags": []string{"x", "y"}}},
+		`{"id": 2, "score": 2, "items": [{"sku": "B-1"}, {"sku": "B-2"}]}`,
+		&event{ID: 3, User: user{Name: "C"}},
+		nil,
+	}
+	for _, row := range rows {
+		if !assert.NoError(t, col.AppendRow(row)) {
+			return
+		}
+	}
+	assert.Error(t, col.AppendRow(42))
+	assert.Error(t, col.AppendRow("{"))
+	if assert.NoError(t, col.(CustomSerialization).WriteStatePrefix(encoder)) && assert.NoError(t, col.Encode(encoder)) {
+		col2, err := Type("Object('json')").Column()
+		if !assert.NoError(t, err) {
+			return
+		}
+		if assert.NoError(t, col2.(CustomSerialization).ReadStatePrefix(decoder)) && assert.NoError(t, col2.Decode(decoder, len(rows))) {
+			var (
+				e1 event
+				e2 event
+				m3 map[string]interface{}
+				s4 string
+			)
+			if assert.NoError(t, col2.ScanRow(&e1, 0)) {
+				assert.Equal(t, int64(1), e1.ID)
+				assert.Equal(t, 1.5, e1.Score)
+				assert.Equal(t, user{Name: "A", Tags: []string{"x", "y"}}, e1.User)
+			}
+			if assert.NoError(t, col2.ScanRow(&e2, 1)) && assert.Len(t, e2.Items, 2) {
+				assert.Equal(t, int64(2), e2.ID)
+				assert.Equal(t, "B-2", e2.Items[1].SKU)
+			}
+			if assert.NoError(t, col2.ScanRow(&m3, 2)) {
+				assert.Equal(t, int64(3), m3["id"])
+				assert.Equal(t, "C", m3["user"].(map[string]interface{})["name"])
+			}
+			if assert.NoError(t, col2.ScanRow(&s4, 3)) {
+				assert.Equal(t, `{"id":0,"items":[],"score":0,"user":{"name":"","tags":[]}}`, s4)
+			}
+		}
+	}
+}
+
+func TestObjectMixedTypes(t *testing.T) {
+	var buffer bytes.Buffer
+	col, err := Type("Object('json')").Column()
+	if assert.NoError(t, err) {
+		assert.NoError(t, col.AppendRow(`{"a": 1}`))
+		assert.NoError(t, col.AppendRow(`{"a": "1"}`))
+		assert.Error(t, col.Encode(binary.NewEncoder(&buffer)))
+	}
+}
+
+func TestNamedTuple(t *testing.T) {
+	col, err := Type("Tuple(id UInt64, user Tuple(name String))").Column()
+	if !assert.NoError(t, err) {
+		return
+	}
+	if assert.NoError(t, col.AppendRow([]interface{}{uint64(42), []interface{}{"A"}})) {
+		var (
+			tuple []interface{}
+			value map[string]interface{}
+			dest  struct {
+				ID   uint64 `json:"id"`
+				User struct {
+					Name string `json:"name"`
+				} `json:"user"`
+			}
+		)
+		if assert.NoError(t, col.ScanRow(&tuple, 0)) {
+			assert.Equal(t, []interface{}{uint64(42), []interface{}{"A"}}, tuple)
+		}
+		if assert.NoError(t, col.ScanRow(&value, 0)) {
+			assert.Equal(t, map[string]interface{}{"id": uint64(42), "user": map[string]interface{}{"name": "A"}}, value)
+		}
+		if assert.NoError(t, col.ScanRow(&dest, 0)) {
+			assert.Equal(t, uint64(42), dest.ID)
+			assert.Equal(t, "A", dest.User.Name)
+		}
+	}
+}
+
+// TestJSONDecode decodes a JSON(max_dynamic_paths=1, id UInt64) column of two rows
+// {"id": 1, "user": {"name": "a"}, "tags": ["x", "y"]} and {"id": 2, "score": 1.5}
+// serialized as the server does: the typed path, the user.name dynamic path and the shared data.
+func TestJSONDecode(t *testing.T) {
+	data := []byte{
+		// state prefix: serialization version 1 (0), max_dynamic_paths, the dynamic paths
+		0, 0, 0, 0, 0, 0, 0, 0,
+		1,
+		1, 9, 'u', 's', 'e', 'r', '.', 'n', 'a', 'm', 'e',
+		// the Dynamic prefix of user.name: version 1, max_types, the types and the discriminators mode
+		1, 0, 0, 0, 0, 0, 0, 0,
+		32,
+		1, 6, 'S', 't', 'r', 'i', 'n', 'g',
+		0, 0, 0, 0, 0, 0, 0, 0,
+		// id
+		1, 0, 0, 0, 0, 0, 0, 0,
+		2, 0, 0, 0, 0, 0, 0, 0,
+		// user.name: the discriminators (SharedVariant, String) and the String variant
+		1, 255,
+		1, 'a',
+		// shared data: the offsets, the paths and the binary encoded values
+		1, 0, 0, 0, 0, 0, 0, 0,
+		2, 0, 0, 0, 0, 0, 0, 0,
+		4, 't', 'a', 'g', 's',
+		5, 's', 'c', 'o', 'r', 'e',
+		// Array(Nullable(String)) ['x', 'y']
+		10, 0x1E, 0x23, 0x15, 2, 0, 1, 'x', 0, 1, 'y',
+		// Float64 1.5
+		9, 0x0E, 0, 0, 0, 0, 0, 0, 0xF8, 0x3F,
+	}
+	col, err := Type("JSON(max_dynamic_paths=1, id UInt64)").Column()
+	if !assert.NoError(t, err) {
+		return
+	}
+	decoder := binary.NewDecoder(bytes.NewReader(data))
+	if assert.NoError(t, col.(CustomSerialization).ReadStatePrefix(decoder)) && assert.NoError(t, col.Decode(decoder, 2)) {
+		var (
+			s1 string
+			e2 struct {
+				ID    uint64  `json:"id"`
+				Score float64 `json:"score"`
+			}
+			m1 map[string]interface{}
+		)
+		if assert.NoError(t, col.ScanRow(&s1, 0)) {
+			assert.Equal(t, `{"id":1,"tags":["x","y"],"user":{"name":"a"}}`, s1)
+		}
+		if assert.NoError(t, col.ScanRow(&m1, 0)) {
+			assert.Equal(t, uint64(1), m1["id"])
+			assert.Equal(t, map[string]interface{}{"name": "a"}, m1["user"])
+		}
+		if assert.NoError(t, col.ScanRow(&e2, 1)) {
+			assert.Equal(t, uint64(2), e2.ID)
+			assert.Equal(t, 1.5, e2.Score)
+		}
+	}
+}
+
+func TestJSON(t *testing.T) {
+	var (
+		buffer  bytes.Buffer
+		decoder = binary.NewDecoder(&buffer)
+		encoder = binary.NewEncoder(&buffer)
+	)
+	type item struct {
+		SKU string `json:"sku"`
+	}
+	type event struct {
+		ID     uint32  `json:"id"`
+		Type   string  `json:"type"`
+		Value  float64 `json:"value"`
+		Items  []item  `json:"items"`
+		Secret string  `json:"secret"`
+	}
+	const columnType = "JSON(max_dynamic_paths=2, id UInt32, SKIP secret)"
+	col, err := Type(columnType).Column()
+	if !assert.NoError(t, err) {
+		return
+	}
+	rows := []interface{}{
+		&event{ID: 1, Type: "click", Value: 1, Secret: "s"},
+		`{"id": 2, "type": "buy", "code": 42, "value": 2.5, "user": {"name": "B"}, "items": [{"sku": "B-1"}, {"sku": "B-2"}]}`,
+		map[string]interface{}{"type": "view", "code": "C-1", "tags": []string{"a", "b"}, "user": map[string]interface{}{"name": "C", "age": 30}},
+		nil,
+	}
+	for _, row := range rows {
+		if !assert.NoError(t, col.AppendRow(row)) {
+			return
+		}
+	}
+	assert.Error(t, col.AppendRow(42))
+	if assert.NoError(t, col.(CustomSerialization).WriteStatePrefix(encoder)) && assert.NoError(t, col.Encode(encoder)) {
+		col2, err := Type(columnType).Column()
+		if !assert.NoError(t, err) {
+			return
+		}
+		if assert.NoError(t, col2.(CustomSerialization).ReadStatePrefix(decoder)) && assert.NoError(t, col2.Decode(decoder, len(rows))) {
+			assert.Equal(t, 0, buffer.Len())
+			var (
+				e1 event
+				e2 event
+				m2 map[string]interface{}
+				s3 string
+				s4 string
+			)
+			if assert.NoError(t, col2.ScanRow(&e1, 0)) {
+				assert.Equal(t, event{ID: 1, Type: "click", Value: 1}, e1)
+			}
+			if assert.NoError(t, col2.ScanRow(&e2, 1)) && assert.Len(t, e2.Items, 2) {
+				assert.Equal(t, uint32(2), e2.ID)
+				assert.Equal(t, "B-2", e2.Items[1].SKU)
+			}
+			if assert.NoError(t, col2.ScanRow(&m2, 1)) {
+				// the types of a path may differ between the rows
+				assert.Equal(t, int64(42), m2["code"])
+				assert.Equal(t, map[string]interface{}{"name": "B"}, m2["user"])
+			}
+			if assert.NoError(t, col2.ScanRow(&s3, 2)) {
+				assert.Equal(t, `{"code":"C-1","id":0,"tags":["a","b"],"type":"view","user":{"age":30,"name":"C"}}`, s3)
+			}
+			if assert.NoError(t, col2.ScanRow(&s4, 3)) {
+				assert.Equal(t, `{"id":0}`, s4)
+			}
+		}
+	}
+}
+
+func TestBinaryType(t *testing.T) {
+	types := []struct {
+		data     []byte
+		expected Type
+	}{
+		{data: []byte{0x2D}, expected: "Bool"},
+		{data: []byte{0x14, 3, 3, 'U', 'T', 'C'}, expected: "DateTime64(3, 'UTC')"},
+		{data: []byte{0x17, 2, 1, 'a', 1, 1, 'b', 2}, expected: "Enum8('a' = 1, 'b' = 2)"},
+		{data: []byte{0x1A, 18, 4}, expected: "Decimal(18, 4)"},
+		{data: []byte{0x27, 0x15, 0x1E, 0x23, 0x0A}, expected: "Map(String, Array(Nullable(Int64)))"},
+		{data: []byte{0x20, 2, 1, 'a', 0x03, 1, 'b', 0x16, 4}, expected: "Tuple(a UInt32, b FixedString(4))"},
+		{data: []byte{0x2A, 2, 0x15, 0x0A}, expected: "Variant(String, Int64)"},
+		{data: []byte{0x2C, 4, 'R', 'i', 'n', 'g'}, expected: "Ring"},
+		{data: []byte{0x30, 0, 0x80, 0x02, 16, 1, 1, 'a', 0x03, 1, 1, 'b', 0}, expected: "JSON(max_dynamic_paths=256, max_dynamic_types=16, a UInt32, SKIP b)"},
+	}
+	for _, tt := range types {
+		if v, err := decodeBinaryType(binary.NewDecoder(bytes.NewReader(tt.data))); assert.NoError(t, err) {
+			assert.Equal(t, tt.expected, v)
+		}
+	}
+	for _, v := range []interface{}{"a", true, int64(-1), 1.5, []*string{nil}, []map[string]interface{}{{"a": "b"}}} {
+		var buffer bytes.Buffer
+		value, err := json.Marshal(v)
+		if !assert.NoError(t, err) {
+			return
+		}
+		var parsed interface{}
+		decoder := json.NewDecoder(bytes.NewReader(value))
+		decoder.UseNumber()
+		if !assert.NoError(t, decoder.Decode(&parsed)) {
+			return
+		}
+		if assert.NoError(t, encodeBinaryJSONValue(binary.NewEncoder(&buffer), parsed)) {
+			if decoded, err := decodeBinaryValue(buffer.String()); assert.NoError(t, err) {
+				assert.Equal(t, v, decoded)
+			}
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"math/big"
 	"reflect"
 	"time"
 
@@ -119,17 +120,28 @@ func (col *LowCardinality) AppendRow(v interface{}) error {
 		col.append.keys = append(col.append.keys, 0)
 		return nil
 	}
+	key := v
 	switch x := v.(type) {
 	case time.Time:
 		v = x.Truncate(time.Second)
+		key = v
+	case big.Int:
+		// big.Int is not comparable, so its decimal representation is used as the dictionary key
+		key = x.String()
+	case *big.Int:
+		if x == nil {
+			col.append.keys = append(col.append.keys, 0)
+			return nil
+		}
+		key = x.String()
 	}
-	if _, found := col.append.index[v]; !found {
+	if _, found := col.append.index[key]; !found {
 		if err := col.index.AppendRow(v); err != nil {
 			return err
 		}
-		col.append.index[v] = col.index.Rows() - 1
+		col.append.index[key] = col.index.Rows() - 1
 	}
-	col.append.keys = append(col.append.keys, col.append.index[v])
+	col.append.keys = append(col.append.keys, col.append.index[key])
 	return nil
 }
 
@@ -2,6 +2,7 @@ package column
 
 import (
 	"fmt"
+	"math/big"
 	"reflect"
 	"strings"
 
@@ -15,6 +16,9 @@ type Map struct {
 	chType   Type
 	offsets  Int64
 	scanType reflect.Type
+	// bigIntKeys is set for the Int128/256 and UInt128/256 keys, big.Int is not comparable
+	// so the keys of the map are the decimal representation of the values (as in LowCardinality)
+	bigIntKeys bool
 }
 
 func (col *Map) parse(t Type) (_ Interface, err error) {
@@ -26,8 +30,18 @@ func (col *Map) parse(t Type) (_ Interface, err error) {
 		if col.values, err = Type(strings.TrimSpace(types[1])).Column(); err != nil {
 			return nil, err
 		}
+		keyType := col.keys.ScanType()
+		if _, ok := col.keys.(*BigInt); ok {
+			col.bigIntKeys, keyType = true, scanTypeString
+		}
+		if keyType != nil && !keyType.Comparable() {
+			return nil, &Error{
+				ColumnType: string(t),
+				Err:        fmt.Errorf("%s can't be used as a map key", col.keys.Type()),
+			}
+		}
 		col.scanType = reflect.MapOf(
-			col.keys.ScanType(),
+			keyType,
 			col.values.ScanType(),
 		)
 		return col, nil
@@ -103,7 +117,18 @@ func (col *Map) AppendRow(v interface{}) error {
 	)
 	for iter.Next() {
 		size++
-		if err := col.keys.AppendRow(iter.Key().Interface()); err != nil {
+		key := iter.Key().Interface()
+		if col.bigIntKeys {
+			v, ok := new(big.Int).SetString(iter.Key().String(), 10)
+			if !ok {
+				return &Error{
+					ColumnType: string(col.chType),
+					Err:        fmt.Errorf("invalid %s map key %q", col.keys.Type(), iter.Key().String()),
+				}
+			}
+			key = v
+		}
+		if err := col.keys.AppendRow(key); err != nil {
 			return err
 		}
 		if err := col.values.AppendRow(iter.Value().Interface()); err != nil {
@@ -180,8 +205,12 @@ func (col *Map) row(n int) reflect.Value {
 		from = int(prev)
 	)
 	for next := 0; next < size; next++ {
+		key := col.keys.Row(from+next, false)
+		if col.bigIntKeys {
+			key = col.keys.Row(from+next, true).(*big.Int).String()
+		}
 		value.SetMapIndex(
-			reflect.ValueOf(col.keys.Row(from+next, false)),
+			reflect.ValueOf(key),
 			reflect.ValueOf(col.values.Row(from+next, false)),
 		)
 	}
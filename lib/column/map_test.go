@@ -0,0 +1,52 @@
+package column
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ClickHouse/clickhouse-go/v2/lib/binary"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMapBigIntKeys(t *testing.T) {
+	var (
+		buffer  bytes.Buffer
+		decoder = binary.NewDecoder(&buffer)
+		encoder = binary.NewEncoder(&buffer)
+		values  = []map[string]string{
+			{
+				"0": "zero",
+				"115792089237316195423570985008687907853269984665640564039457584007913129639935": "max",
+			},
+			{},
+			{"42": "answer"},
+		}
+	)
+	col, err := Type("Map(UInt256, String)").Column()
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "map[string]string", col.ScanType().String())
+	for _, v := range values {
+		if !assert.NoError(t, col.AppendRow(v)) {
+			return
+		}
+	}
+	if assert.NoError(t, col.Encode(encoder)) {
+		col2, err := Type("Map(UInt256, String)").Column()
+		if !assert.NoError(t, err) {
+			return
+		}
+		if assert.NoError(t, col2.Decode(decoder, len(values))) {
+			for i, expected := range values {
+				var v map[string]string
+				if assert.NoError(t, col2.ScanRow(&v, i)) {
+					assert.Equal(t, expected, v)
+				}
+			}
+		}
+	}
+	assert.Error(t, col.AppendRow(map[string]string{"0x2a": "hex"}))
+	// UInt256 does not take the negative values
+	assert.Error(t, col.AppendRow(map[string]string{"-1": "negative"}))
+}
@@ -0,0 +1,105 @@
+package column
+
+import (
+	"time"
+)
+
+// IsNullable reports whether the column stores NULL values (Nullable and LowCardinality(Nullable) columns).
+func IsNullable(col Interface) bool {
+	switch col := col.(type) {
+	case *Nullable:
+		return true
+	case *LowCardinality:
+		return col.nullable
+	}
+	return false
+}
+
+// PrecisionScale returns the precision and the scale of the Decimal columns.
+func PrecisionScale(col Interface) (precision, scale int64, ok bool) {
+	switch col := unwrap(col).(type) {
+	case *Decimal:
+		return col.Precision(), col.Scale(), true
+	}
+	return 0, 0, false
+}
+
+// Timezone returns the time zone of the DateTime and DateTime64 columns, nil if the type has no time zone.
+func Timezone(col Interface) *time.Location {
+	switch col := unwrap(col).(type) {
+	case *DateTime:
+		return col.timezone
+	case *DateTime64:
+		return col.timezone
+	}
+	return nil
+}
+
+// EnumValues returns the values of the Enum8 and Enum16 columns by their names.
+func EnumValues(col Interface) map[string]int {
+	values := make(map[string]int)
+	switch col := unwrap(col).(type) {
+	case *Enum8:
+		for name, v := range col.iv {
+			values[name] = int(int8(v))
+		}
+	case *Enum16:
+		for name, v := range col.iv {
+			values[name] = int(int16(v))
+		}
+	default:
+		return nil
+	}
+	return values
+}
+
+// Elements returns the columns the composite type is made of and their names (empty if the elements have no names):
+// the element of Array, Nullable and LowCardinality, the keys and the values of Map, the elements of Tuple and Nested,
+// the variants of Variant and the type of the values of SimpleAggregateFunction.
+func Elements(col Interface) (names []string, elements []Interface) {
+	switch col := col.(type) {
+	case *Array:
+		element, err := Type(col.chType.params()).Column()
+		if err != nil {
+			return nil, nil
+		}
+		return []string{""}, []Interface{element}
+	case *Nullable:
+		return []string{""}, []Interface{col.base}
+	case *LowCardinality:
+		return []string{""}, []Interface{col.index}
+	case *SimpleAggregateFunction:
+		return []string{""}, []Interface{col.base}
+	case *Map:
+		return []string{"keys", "values"}, []Interface{col.keys, col.values}
+	case *Tuple:
+		names = col.names
+		if names == nil {
+			names = make([]string, len(col.columns))
+		}
+		return names, col.columns
+	case *Nested:
+		// Nested is an array of tuples
+		if _, elements := Elements(col.Interface); len(elements) == 1 {
+			return Elements(elements[0])
+		}
+	case *Variant:
+		names = make([]string, 0, len(col.types))
+		for _, t := range col.types {
+			names = append(names, string(t))
+		}
+		return names, col.columns
+	}
+	return nil, nil
+}
+
+// unwrap returns the type of the values of the Nullable and LowCardinality columns.
+func unwrap(col Interface) Interface {
+	switch c := col.(type) {
+	case *Nullable:
+		return unwrap(c.base)
+	case *LowCardinality:
+		return unwrap(c.index)
+	}
+	return col
+}
@@ -0,0 +1,56 @@
+package column
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetadata(t *testing.T) {
+	column := func(chType Type) Interface {
+		col, err := chType.Column()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return col
+	}
+	assert.True(t, IsNullable(column("Nullable(String)")))
+	assert.True(t, IsNullable(column("LowCardinality(Nullable(String))")))
+	assert.False(t, IsNullable(column("Array(Nullable(String))")))
+
+	if precision, scale, ok := PrecisionScale(column("Nullable(Decimal(18, 4))")); assert.True(t, ok) {
+		assert.Equal(t, int64(18), precision)
+		assert.Equal(t, int64(4), scale)
+	}
+	_, _, ok := PrecisionScale(column("Float64"))
+	assert.False(t, ok)
+
+	if tz := Timezone(column("DateTime64(3, 'Europe/Moscow')")); assert.NotNil(t, tz) {
+		assert.Equal(t, "Europe/Moscow", tz.String())
+	}
+	assert.Nil(t, Timezone(column("DateTime")))
+
+	assert.Equal(t, map[string]int{"a": -1, "b": 2}, EnumValues(column("Enum8('a' = -1, 'b' = 2)")))
+	assert.Nil(t, EnumValues(column("String")))
+
+	for _, asset := range []struct {
+		chType   Type
+		names    []string
+		elements []Type
+	}{
+		{chType: "Array(Array(UInt8))", names: []string{""}, elements: []Type{"Array(UInt8)"}},
+		{chType: "Map(String, UInt64)", names: []string{"keys", "values"}, elements: []Type{"String", "UInt64"}},
+		{chType: "Tuple(a String, b Int8)", names: []string{"a", "b"}, elements: []Type{"String", "Int8"}},
+		{chType: "Tuple(String, Int8)", names: []string{"", ""}, elements: []Type{"String", "Int8"}},
+		{chType: "Nested(a String, b Int8)", names: []string{"a", "b"}, elements: []Type{"String", "Int8"}},
+		{chType: "String"},
+	} {
+		names, elements := Elements(column(asset.chType))
+		var types []Type
+		for _, e := range elements {
+			types = append(types, e.Type())
+		}
+		assert.Equal(t, asset.names, names, string(asset.chType))
+		assert.Equal(t, asset.elements, types, string(asset.chType))
+	}
+}
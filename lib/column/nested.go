@@ -0,0 +1,55 @@
+package column
+
+import (
+	"fmt"
+
+	"github.com/ClickHouse/clickhouse-go/v2/lib/binary"
+)
+
+// Nested is used when the nested data structures are not flattened (flatten_nested = 0).
+// On the wire Nested(a T, b U) is the same as Array(Tuple(T, U)).
+// https://clickhouse.com/docs/en/sql-reference/data-types/nested-data-structures/nested/
+type Nested struct {
+	Interface
+	chType Type
+}
+
+func (col *Nested) parse(t Type) (_ Interface, err error) {
+	col.chType = t
+	if len(t.params()) == 0 {
+		return &UnsupportedColumnType{
+			t: t,
+		}, nil
+	}
+	if col.Interface, err = Type(fmt.Sprintf("Array(Tuple(%s))", t.params())).Column(); err != nil {
+		return nil, err
+	}
+	return col, nil
+}
+
+func (col *Nested) Type() Type {
+	return col.chType
+}
+
+func (col *Nested) ReadStatePrefix(decoder *binary.Decoder) error {
+	if serialize, ok := col.Interface.(CustomSerialization); ok {
+		if err := serialize.ReadStatePrefix(decoder); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (col *Nested) WriteStatePrefix(encoder *binary.Encoder) error {
+	if serialize, ok := col.Interface.(CustomSerialization); ok {
+		if err := serialize.WriteStatePrefix(encoder); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var (
+	_ Interface           = (*Nested)(nil)
+	_ CustomSerialization = (*Nested)(nil)
+)
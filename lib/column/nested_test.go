@@ -0,0 +1,42 @@
+package column
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ClickHouse/clickhouse-go/v2/lib/binary"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNested(t *testing.T) {
+	var (
+		buffer  bytes.Buffer
+		decoder = binary.NewDecoder(&buffer)
+		encoder = binary.NewEncoder(&buffer)
+		chType  = Type("Nested(Key String, Value Nullable(UInt32))")
+		value   = uint32(42)
+		rows    = [][][]interface{}{
+			{{"A", &value}, {"B", nil}},
+			{},
+		}
+	)
+	col, err := chType.Column()
+	if assert.NoError(t, err) && assert.Equal(t, chType, col.Type()) {
+		for _, row := range rows {
+			if !assert.NoError(t, col.AppendRow(row)) {
+				return
+			}
+		}
+		if assert.NoError(t, col.Encode(encoder)) {
+			col2, err := chType.Column()
+			if !assert.NoError(t, err) {
+				return
+			}
+			if assert.NoError(t, col2.Decode(decoder, len(rows))) {
+				for i, row := range rows {
+					assert.Equal(t, row, col2.Row(i, false))
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,448 @@
+package column
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/ClickHouse/clickhouse-go/v2/lib/binary"
+)
+
+// Object is the deprecated Object('json') type. Its dynamic subcolumns are sent over the wire
+// as a named tuple which type precedes the data, e.g. Tuple(id Int64, user Tuple(name String)).
+// The tuple type of the inserted rows is inferred from their values.
+// https://clickhouse.com/docs/en/sql-reference/data-types/object-data-type
+type Object struct {
+	chType Type
+	rows   []map[string]interface{}
+}
+
+// objectSerializationKind is the serialization kind of the Object data, only TUPLE is supported.
+const objectSerializationKind = 0
+
+func (col *Object) Type() Type {
+	return col.chType
+}
+
+func (col *Object) ScanType() reflect.Type {
+	return scanTypeJSON
+}
+
+func (col *Object) Rows() int {
+	return len(col.rows)
+}
+
+func (col *Object) Row(i int, ptr bool) interface{} {
+	value := col.rows[i]
+	if ptr {
+		return &value
+	}
+	return value
+}
+
+func (col *Object) ScanRow(dest interface{}, row int) error {
+	switch d := dest.(type) {
+	case *map[string]interface{}:
+		*d = col.rows[row]
+	case *string:
+		v, err := json.Marshal(col.rows[row])
+		if err != nil {
+			return err
+		}
+		*d = string(v)
+	default:
+		if isStructPtr(dest) {
+			return scanJSON(col.rows[row], dest)
+		}
+		return &ColumnConverterError{
+			Op:   "ScanRow",
+			To:   fmt.Sprintf("%T", dest),
+			From: string(col.chType),
+			Hint: fmt.Sprintf("try using *%s, *string or a pointer to a struct", scanTypeJSON),
+		}
+	}
+	return nil
+}
+
+func (col *Object) Append(v interface{}) (nulls []uint8, err error) {
+	value := reflect.ValueOf(v)
+	if value.Kind() != reflect.Slice {
+		return nil, &ColumnConverterError{
+			Op:   "Append",
+			To:   string(col.chType),
+			From: fmt.Sprintf("%T", v),
+			Hint: "value must be a slice",
+		}
+	}
+	nulls = make([]uint8, value.Len())
+	for i := 0; i < value.Len(); i++ {
+		if err := col.AppendRow(value.Index(i).Interface()); err != nil {
+			return nil, err
+		}
+	}
+	return
+}
+
+func (col *Object) AppendRow(v interface{}) error {
+	row, err := jsonObject(col.chType, v)
+	if err != nil {
+		return err
+	}
+	col.rows = append(col.rows, row)
+	return nil
+}
+
+func (col *Object) Decode(decoder *binary.Decoder, rows int) error {
+	tupleType, err := decoder.String()
+	if err != nil {
+		return err
+	}
+	c, err := Type(tupleType).Column()
+	if err != nil {
+		return err
+	}
+	tuple, ok := c.(*Tuple)
+	if !ok || len(tuple.names) == 0 {
+		return &Error{
+			ColumnType: string(col.chType),
+			Err:        fmt.Errorf("unexpected type of the object data %s", tupleType),
+		}
+	}
+	if err := tuple.ReadStatePrefix(decoder); err != nil {
+		return err
+	}
+	if err := tuple.Decode(decoder, rows); err != nil {
+		return err
+	}
+	for i := 0; i < rows; i++ {
+		col.rows = append(col.rows, tuple.mapRow(i))
+	}
+	return nil
+}
+
+func (col *Object) Encode(encoder *binary.Encoder) error {
+	var root jsonNode
+	for _, row := range col.rows {
+		if err := root.merge(row); err != nil {
+			return &Error{
+				ColumnType: string(col.chType),
+				Err:        err,
+			}
+		}
+	}
+	var (
+		tupleType = root.Type()
+		values    = make([]interface{}, 0, len(col.rows))
+	)
+	for _, row := range col.rows {
+		values = append(values, root.value(row))
+	}
+	if len(tupleType) == 0 {
+		// an object without paths has a dummy subcolumn
+		tupleType = "Tuple(_dummy UInt8)"
+		for i := range values {
+			values[i] = []interface{}{uint8(0)}
+		}
+	}
+	c, err := tupleType.Column()
+	if err != nil {
+		return err
+	}
+	for _, v := range values {
+		if err := c.AppendRow(v); err != nil {
+			return err
+		}
+	}
+	if err := encoder.String(string(tupleType)); err != nil {
+		return err
+	}
+	if serialize, ok := c.(CustomSerialization); ok {
+		if err := serialize.WriteStatePrefix(encoder); err != nil {
+			return err
+		}
+	}
+	return c.Encode(encoder)
+}
+
+func (col *Object) ReadStatePrefix(decoder *binary.Decoder) error {
+	kind, err := decoder.UInt8()
+	if err != nil {
+		return err
+	}
+	if kind != objectSerializationKind {
+		return &Error{
+			ColumnType: string(col.chType),
+			Err:        fmt.Errorf("unsupported serialization kind %d", kind),
+		}
+	}
+	return nil
+}
+
+func (col *Object) WriteStatePrefix(encoder *binary.Encoder) error {
+	return encoder.UInt8(objectSerializationKind)
+}
+
+// jsonObject converts a map, a struct or a JSON string to the object appended to the column.
+// The values are normalized through encoding/json, the numbers are kept as json.Number.
+func jsonObject(chType Type, v interface{}) (map[string]interface{}, error) {
+	var data []byte
+	switch v := v.(type) {
+	case string:
+		data = []byte(v)
+	case []byte:
+		data = v
+	case nil:
+		data = []byte("{}")
+	default:
+		switch value := reflect.Indirect(reflect.ValueOf(v)); value.Kind() {
+		case reflect.Map, reflect.Struct:
+		default:
+			return nil, &ColumnConverterError{
+				Op:   "AppendRow",
+				To:   string(chType),
+				From: fmt.Sprintf("%T", v),
+				Hint: "try using map[string]interface{}, a struct or a JSON string",
+			}
+		}
+		var err error
+		if data, err = json.Marshal(v); err != nil {
+			return nil, &Error{
+				ColumnType: string(chType),
+				Err:        err,
+			}
+		}
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var row map[string]interface{}
+	if err := decoder.Decode(&row); err != nil {
+		return nil, &Error{
+			ColumnType: string(chType),
+			Err:        err,
+		}
+	}
+	if row == nil {
+		row = map[string]interface{}{}
+	}
+	return row, nil
+}
+
+// jsonNode is the inferred type of a JSON value: a scalar, an object (named tuple) or an array.
+type jsonNode struct {
+	chType Type
+	object map[string]*jsonNode
+	array  *jsonNode
+}
+
+func (n *jsonNode) merge(v interface{}) error {
+	switch v := v.(type) {
+	case nil:
+		return nil
+	case map[string]interface{}:
+		if len(n.chType) != 0 || n.array != nil {
+			return errors.New("mixed object and non object values")
+		}
+		if n.object == nil {
+			n.object = make(map[string]*jsonNode)
+		}
+		for key, value := range v {
+			node, found := n.object[key]
+			if !found {
+				node = &jsonNode{}
+				n.object[key] = node
+			}
+			if err := node.merge(value); err != nil {
+				return fmt.Errorf("%s: %w", key, err)
+			}
+		}
+		return nil
+	case []interface{}:
+		if len(n.chType) != 0 || n.object != nil {
+			return errors.New("mixed array and non array values")
+		}
+		if n.array == nil {
+			n.array = &jsonNode{}
+		}
+		for _, value := range v {
+			if err := n.array.merge(value); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if n.object != nil || n.array != nil {
+		return errors.New("mixed scalar and non scalar values")
+	}
+	var chType Type
+	switch v := v.(type) {
+	case string:
+		chType = "String"
+	case bool:
+		chType = "Bool"
+	case json.Number:
+		chType = "Float64"
+		if _, err := v.Int64(); err == nil {
+			chType = "Int64"
+		}
+	default:
+		return fmt.Errorf("unexpected value %T", v)
+	}
+	switch {
+	case len(n.chType) == 0, n.chType == chType:
+		n.chType = chType
+	case n.chType == "Float64" && chType == "Int64", n.chType == "Int64" && chType == "Float64":
+		n.chType = "Float64"
+	default:
+		return fmt.Errorf("mixed %s and %s values", n.chType, chType)
+	}
+	return nil
+}
+
+// Type returns the ClickHouse type of the node, objects without paths have no type.
+func (n *jsonNode) Type() Type {
+	switch {
+	case n.object != nil:
+		var elements []string
+		for _, key := range n.keys() {
+			elements = append(elements, fmt.Sprintf("%s %s", key, n.object[key].Type()))
+		}
+		if len(elements) == 0 {
+			return ""
+		}
+		return Type(fmt.Sprintf("Tuple(%s)", strings.Join(elements, ", ")))
+	case n.array != nil:
+		elem := n.array.Type()
+		switch {
+		case n.array.object != nil && len(elem) != 0:
+			return Type("Nested" + strings.TrimPrefix(string(elem), "Tuple"))
+		case len(elem) == 0:
+			// the type of the elements of empty arrays is unknown
+			elem = "String"
+		}
+		return Type(fmt.Sprintf("Array(%s)", elem))
+	}
+	return n.chType
+}
+
+// keys returns the sorted keys of the object paths which have a type.
+func (n *jsonNode) keys() []string {
+	keys := make([]string, 0, len(n.object))
+	for key, node := range n.object {
+		if len(node.Type()) != 0 {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (n *jsonNode) scanType() reflect.Type {
+	switch {
+	case n.object != nil:
+		return scanTypeSlice
+	case n.array != nil:
+		if len(n.array.Type()) == 0 {
+			return reflect.SliceOf(scanTypeString)
+		}
+		return reflect.SliceOf(n.array.scanType())
+	}
+	switch n.chType {
+	case "String":
+		return scanTypeString
+	case "Bool":
+		return scanTypeBool
+	case "Int64":
+		return scanTypeInt64
+	}
+	return scanTypeFloat64
+}
+
+// value converts v to the value appended to the column of the node. Missing values are replaced by the defaults.
+func (n *jsonNode) value(v interface{}) interface{} {
+	switch {
+	case n.object != nil:
+		object, _ := v.(map[string]interface{})
+		keys := n.keys()
+		tuple := make([]interface{}, 0, len(keys))
+		for _, key := range keys {
+			tuple = append(tuple, n.object[key].value(object[key]))
+		}
+		return tuple
+	case n.array != nil:
+		array, _ := v.([]interface{})
+		if len(n.array.Type()) == 0 {
+			return []string{}
+		}
+		slice := reflect.MakeSlice(n.scanType(), 0, len(array))
+		for _, elem := range array {
+			slice = reflect.Append(slice, reflect.ValueOf(n.array.value(elem)))
+		}
+		return slice.Interface()
+	}
+	switch n.chType {
+	case "String":
+		v, _ := v.(string)
+		return v
+	case "Bool":
+		v, _ := v.(bool)
+		return v
+	case "Int64":
+		number, _ := v.(json.Number)
+		v, _ := number.Int64()
+		return v
+	}
+	number, _ := v.(json.Number)
+	f, _ := number.Float64()
+	return f
+}
+
+// jsonRow returns the value of the row with the named tuples converted to maps.
+func jsonRow(col Interface, row int) interface{} {
+	switch c := col.(type) {
+	case *Tuple:
+		if len(c.names) != 0 {
+			return c.mapRow(row)
+		}
+	case *Nested:
+		if array, ok := c.Interface.(*Array); ok && array.depth == 1 {
+			if tuple, ok := array.Base().(*Tuple); ok && len(tuple.names) != 0 {
+				var (
+					start   uint64
+					offsets = array.Offsets()
+				)
+				if row > 0 {
+					start = offsets[row-1]
+				}
+				value := make([]interface{}, 0, offsets[row]-start)
+				for i := start; i < offsets[row]; i++ {
+					value = append(value, tuple.mapRow(int(i)))
+				}
+				return value
+			}
+		}
+	}
+	return col.Row(row, false)
+}
+
+// scanJSON scans the map into the struct using its json tags.
+func scanJSON(value map[string]interface{}, dest interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, dest)
+}
+
+func isStructPtr(v interface{}) bool {
+	value := reflect.ValueOf(v)
+	return value.Kind() == reflect.Ptr && !value.IsNil() && value.Elem().Kind() == reflect.Struct
+}
+
+var (
+	_ Interface           = (*Object)(nil)
+	_ CustomSerialization = (*Object)(nil)
+)
@@ -0,0 +1,52 @@
+package column
+
+import (
+	"reflect"
+)
+
+// Slice returns the values of the column as a slice of its scan type ([]int64, []string, []time.Time, []*string ...).
+// The numeric and String columns return the slice the column is decoded into, the other columns are copied.
+func Slice(col Interface) interface{} {
+	switch col := col.(type) {
+	case *Float32:
+		return []float32(*col)
+	case *Float64:
+		return []float64(*col)
+	case *Int8:
+		return []int8(*col)
+	case *Int16:
+		return []int16(*col)
+	case *Int32:
+		return []int32(*col)
+	case *Int64:
+		return []int64(*col)
+	case *UInt8:
+		return []uint8(*col)
+	case *UInt16:
+		return []uint16(*col)
+	case *UInt32:
+		return []uint32(*col)
+	case *UInt64:
+		return []uint64(*col)
+	case *String:
+		return []string(*col)
+	}
+	scanType := col.ScanType()
+	if scanType == nil {
+		return nil
+	}
+	slice := reflect.MakeSlice(reflect.SliceOf(scanType), col.Rows(), col.Rows())
+	for i := 0; i < col.Rows(); i++ {
+		value := col.Row(i, false)
+		if value == nil {
+			continue
+		}
+		switch v := reflect.ValueOf(value); {
+		case v.Type().AssignableTo(scanType):
+			slice.Index(i).Set(v)
+		case v.Type().ConvertibleTo(scanType):
+			slice.Index(i).Set(v.Convert(scanType))
+		}
+	}
+	return slice.Interface()
+}
@@ -0,0 +1,45 @@
+package column
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSlice(t *testing.T) {
+	var (
+		now    = time.Unix(time.Now().Unix(), 0)
+		value  = "b"
+		assets = []struct {
+			chType   Type
+			values   []interface{}
+			expected interface{}
+		}{
+			{chType: "Int64", values: []interface{}{int64(1), int64(2)}, expected: []int64{1, 2}},
+			{chType: "String", values: []interface{}{"a", "b"}, expected: []string{"a", "b"}},
+			{chType: "DateTime", values: []interface{}{now}, expected: []time.Time{now}},
+			{chType: "Nullable(String)", values: []interface{}{nil, &value}, expected: []*string{nil, &value}},
+			{chType: "Array(UInt8)", values: []interface{}{[]uint8{1, 2}}, expected: [][]uint8{{1, 2}}},
+		}
+	)
+	for _, asset := range assets {
+		col, err := asset.chType.Column()
+		if !assert.NoError(t, err) {
+			return
+		}
+		for _, v := range asset.values {
+			if !assert.NoError(t, col.AppendRow(v)) {
+				return
+			}
+		}
+		assert.Equal(t, asset.expected, Slice(col), string(asset.chType))
+	}
+}
+
+func TestSliceNoCopy(t *testing.T) {
+	col := &UInt32{1, 2}
+	slice := Slice(col).([]uint32)
+	slice[0] = 42
+	assert.Equal(t, uint32(42), col.Row(0, false))
+}
@@ -0,0 +1,105 @@
+package column
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ClickHouse/clickhouse-go/v2/lib/binary"
+)
+
+// serialization kinds of the column data
+const (
+	serializationKindDefault = 0
+	serializationKindSparse  = 1
+)
+
+// sparseEndOfGranule marks the number of the trailing default values of the sparse column.
+const sparseEndOfGranule = 1 << 62
+
+// ReadSerializationKind reads the serialization kinds of the column (the kinds of the elements follow the kind of a tuple)
+// and reports whether the data of the column is sparse.
+func ReadSerializationKind(decoder *binary.Decoder, col Interface) (sparse bool, err error) {
+	kind, err := decoder.UInt8()
+	if err != nil {
+		return false, err
+	}
+	switch kind {
+	case serializationKindDefault:
+	case serializationKindSparse:
+		sparse = true
+	default:
+		return false, &Error{
+			ColumnType: string(col.Type()),
+			Err:        fmt.Errorf("unsupported serialization kind %d", kind),
+		}
+	}
+	if tuple, ok := col.(*Tuple); ok {
+		for _, c := range tuple.columns {
+			sparseElement, err := ReadSerializationKind(decoder, c)
+			if err != nil {
+				return false, err
+			}
+			if sparseElement {
+				return false, &Error{
+					ColumnType: string(col.Type()),
+					Err:        errors.New("sparse serialization of the tuple elements is not supported"),
+				}
+			}
+		}
+	}
+	return sparse, nil
+}
+
+// DecodeSparse reads the sparse serialization of the column: the state prefix, the positions of
+// the non default values and the non default values. The values are appended to the column with the defaults between them.
+func DecodeSparse(decoder *binary.Decoder, col Interface, rows int) error {
+	values, err := col.Type().Column()
+	if err != nil {
+		return err
+	}
+	if serialize, ok := values.(CustomSerialization); ok {
+		if err := serialize.ReadStatePrefix(decoder); err != nil {
+			return err
+		}
+	}
+	var (
+		row       int
+		positions []int
+	)
+	for {
+		size, err := decoder.Uvarint()
+		if err != nil {
+			return err
+		}
+		if size&sparseEndOfGranule != 0 {
+			row += int(size &^ sparseEndOfGranule)
+			break
+		}
+		row += int(size)
+		positions = append(positions, row)
+		row++
+	}
+	if row != rows {
+		return &Error{
+			ColumnType: string(col.Type()),
+			Err:        fmt.Errorf("sparse offsets cover %d rows, expected %d", row, rows),
+		}
+	}
+	if len(positions) != 0 {
+		if err := values.Decode(decoder, len(positions)); err != nil {
+			return err
+		}
+	}
+	for i, n := 0, 0; i < rows; i++ {
+		// nil appends the default of the column: NULL or the zero value of the type as on the server
+		// (the zero value of the Go type is not valid for all the types, e.g. the dates and the enums)
+		var value interface{}
+		if n < len(positions) && positions[n] == i {
+			value, n = values.Row(n, false), n+1
+		}
+		if err := col.AppendRow(value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package column
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/ClickHouse/clickhouse-go/v2/lib/binary"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeSparse(t *testing.T) {
+	var (
+		epoch = time.Unix(0, 0).UTC()
+		value = time.Date(2022, 3, 4, 5, 6, 7, 0, time.UTC)
+		str   = "x"
+	)
+	columns := []struct {
+		chType   Type
+		value    interface{}
+		expected []interface{}
+	}{
+		{chType: "DateTime('UTC')", value: value, expected: []interface{}{epoch, value, epoch, epoch}},
+		{chType: "DateTime64(3, 'UTC')", value: value, expected: []interface{}{epoch, value, epoch, epoch}},
+		{chType: "Date", value: time.Date(2022, 3, 4, 0, 0, 0, 0, time.UTC), expected: []interface{}{epoch, time.Date(2022, 3, 4, 0, 0, 0, 0, time.UTC), epoch, epoch}},
+		// the default of the enums is the zero value, as on the server
+		{chType: "Enum8('a' = 1, 'b' = 2)", value: "b", expected: []interface{}{"", "b", "", ""}},
+		{chType: "Enum16('a' = 1, 'b' = 2)", value: "a", expected: []interface{}{"", "a", "", ""}},
+		{chType: "Nullable(String)", value: str, expected: []interface{}{nil, &str, nil, nil}},
+	}
+	for _, c := range columns {
+		var buffer bytes.Buffer
+		encoder := binary.NewEncoder(&buffer)
+		// one default, the value and two trailing defaults
+		if !assert.NoError(t, encoder.Uvarint(1)) || !assert.NoError(t, encoder.Uvarint(sparseEndOfGranule|2)) {
+			return
+		}
+		values, err := c.chType.Column()
+		if !assert.NoError(t, err) || !assert.NoError(t, values.AppendRow(c.value)) || !assert.NoError(t, values.Encode(encoder)) {
+			return
+		}
+		col, err := c.chType.Column()
+		if !assert.NoError(t, err) {
+			return
+		}
+		if assert.NoError(t, DecodeSparse(binary.NewDecoder(&buffer), col, len(c.expected)), c.chType) {
+			for i, expected := range c.expected {
+				assert.Equal(t, expected, col.Row(i, false), c.chType)
+			}
+		}
+	}
+}
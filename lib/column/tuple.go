@@ -10,6 +10,7 @@ import (
 
 type Tuple struct {
 	chType  Type
+	names   []string
 	columns []Interface
 }
 
@@ -18,16 +19,20 @@ func (col *Tuple) parse(t Type) (_ Interface, err error) {
 	var (
 		element       []rune
 		elements      []string
+		names         []string
 		brackets      int
 		appendElement = func() {
 			if len(element) != 0 {
-				name := strings.TrimSpace(string(element))
-				if parts := strings.SplitN(name, " ", 2); len(parts) == 2 {
+				var (
+					name  string
+					cType = strings.TrimSpace(string(element))
+				)
+				if parts := strings.SplitN(cType, " ", 2); len(parts) == 2 {
 					if !strings.Contains(parts[0], "(") {
-						name = parts[1]
+						name, cType = parts[0], parts[1]
 					}
 				}
-				elements = append(elements, name)
+				names, elements = append(names, name), append(elements, cType)
 			}
 		}
 	)
@@ -54,7 +59,13 @@ func (col *Tuple) parse(t Type) (_ Interface, err error) {
 		}
 		col.columns = append(col.columns, column)
 	}
-	if len(col.columns) != 0 {
+	for _, name := range names {
+		if len(name) == 0 {
+			names = nil
+			break
+		}
+	}
+	if col.names = names; len(col.columns) != 0 {
 		return col, nil
 	}
 	return &UnsupportedColumnType{
@@ -93,7 +104,20 @@ func (col *Tuple) ScanRow(dest interface{}, row int) error {
 			tuple = append(tuple, c.Row(row, false))
 		}
 		*d = tuple
+	case *map[string]interface{}:
+		if len(col.names) == 0 {
+			return &ColumnConverterError{
+				Op:   "ScanRow",
+				To:   fmt.Sprintf("%T", dest),
+				From: string(col.chType),
+				Hint: "only named tuples can be scanned into a map",
+			}
+		}
+		*d = col.mapRow(row)
 	default:
+		if len(col.names) != 0 && isStructPtr(dest) {
+			return scanJSON(col.mapRow(row), dest)
+		}
 		return &ColumnConverterError{
 			Op:   "ScanRow",
 			To:   fmt.Sprintf("%T", dest),
@@ -161,6 +185,15 @@ func (col *Tuple) Encode(encoder *binary.Encoder) error {
 	return nil
 }
 
+// mapRow converts a row of a named tuple to a map. The nested named tuples become nested maps.
+func (col *Tuple) mapRow(row int) map[string]interface{} {
+	value := make(map[string]interface{}, len(col.names))
+	for i, name := range col.names {
+		value[name] = jsonRow(col.columns[i], row)
+	}
+	return value
+}
+
 func (col *Tuple) ReadStatePrefix(decoder *binary.Decoder) error {
 	for _, c := range col.columns {
 		if serialize, ok := c.(CustomSerialization); ok {
@@ -0,0 +1,310 @@
+package column
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/ClickHouse/clickhouse-go/v2/lib/binary"
+)
+
+// NullVariantDiscriminator is the discriminator of the NULL rows of a Variant column.
+const NullVariantDiscriminator = 255
+
+// basic mode of the discriminators serialization (one UInt8 per row)
+const variantDiscriminatorsBasic = 0
+
+// VariantValue is a value with an explicit ClickHouse type. Appending it chooses the variant of a
+// Variant column (or the type of a Dynamic column) instead of inferring it from the Go type.
+// Scanning into it returns the value of the row along with its type.
+type VariantValue struct {
+	Type  Type
+	Value interface{}
+}
+
+// Variant stores a value of one of the variant types (or NULL) per row.
+// The variants are sorted by their type name, the discriminator of a row is the index of its variant.
+// https://clickhouse.com/docs/en/sql-reference/data-types/variant
+type Variant struct {
+	chType         Type
+	types          []Type
+	columns        []Interface
+	discriminators []uint8
+	offsets        []int
+}
+
+func (col *Variant) parse(t Type) (_ Interface, err error) {
+	col.chType = t
+	params := splitTypeParams(t.params())
+	if len(params) == 0 {
+		return &UnsupportedColumnType{
+			t: t,
+		}, nil
+	}
+	for _, param := range params {
+		c, err := Type(param).Column()
+		if err != nil {
+			return nil, err
+		}
+		col.addVariant(Type(param), c)
+	}
+	return col, nil
+}
+
+func (col *Variant) Type() Type {
+	return col.chType
+}
+
+func (col *Variant) ScanType() reflect.Type {
+	return scanTypeAny
+}
+
+func (col *Variant) Rows() int {
+	return len(col.discriminators)
+}
+
+// Discriminator returns the index of the variant of the row, NullVariantDiscriminator for NULL.
+func (col *Variant) Discriminator(row int) uint8 {
+	return col.discriminators[row]
+}
+
+// RowType returns the type of the variant of the row, an empty type for NULL.
+func (col *Variant) RowType(row int) Type {
+	if d := col.discriminators[row]; d != NullVariantDiscriminator {
+		return col.types[d]
+	}
+	return ""
+}
+
+func (col *Variant) Row(i int, ptr bool) interface{} {
+	if d := col.discriminators[i]; d != NullVariantDiscriminator {
+		return col.columns[d].Row(col.offsets[i], ptr)
+	}
+	return nil
+}
+
+func (col *Variant) ScanRow(dest interface{}, row int) error {
+	switch d := dest.(type) {
+	case *interface{}:
+		*d = col.Row(row, false)
+	case *VariantValue:
+		*d = VariantValue{
+			Type:  col.RowType(row),
+			Value: col.Row(row, false),
+		}
+	default:
+		if d := col.discriminators[row]; d != NullVariantDiscriminator {
+			return col.columns[d].ScanRow(dest, col.offsets[row])
+		}
+		// NULL is scanned as the nil pointer, the other destinations can not hold it
+		if value := reflect.ValueOf(dest); value.Kind() == reflect.Ptr && value.Elem().Kind() == reflect.Ptr {
+			value.Elem().Set(reflect.Zero(value.Elem().Type()))
+			return nil
+		}
+		return &ColumnConverterError{
+			Op:   "ScanRow",
+			To:   fmt.Sprintf("%T", dest),
+			From: "NULL",
+			Hint: "try using a pointer to a pointer or *interface{}",
+		}
+	}
+	return nil
+}
+
+func (col *Variant) Append(v interface{}) (nulls []uint8, err error) {
+	value := reflect.ValueOf(v)
+	if value.Kind() != reflect.Slice {
+		return nil, &ColumnConverterError{
+			Op:   "Append",
+			To:   string(col.chType),
+			From: fmt.Sprintf("%T", v),
+			Hint: "value must be a slice",
+		}
+	}
+	nulls = make([]uint8, value.Len())
+	for i := 0; i < value.Len(); i++ {
+		if err := col.AppendRow(value.Index(i).Interface()); err != nil {
+			return nil, err
+		}
+	}
+	return
+}
+
+func (col *Variant) AppendRow(v interface{}) error {
+	if value, ok := v.(VariantValue); ok {
+		if value.Value == nil {
+			return col.appendNull()
+		}
+		if idx := col.variant(value.Type); idx != -1 {
+			return col.append(idx, value.Value)
+		}
+		return &Error{
+			ColumnType: string(col.chType),
+			Err:        fmt.Errorf("unknown variant %s", value.Type),
+		}
+	}
+	value := reflect.ValueOf(v)
+	for value.Kind() == reflect.Ptr && !value.IsNil() {
+		value = value.Elem()
+	}
+	if !value.IsValid() || value.Kind() == reflect.Ptr {
+		return col.appendNull()
+	}
+	for i, c := range col.columns {
+		if c.ScanType() == value.Type() {
+			return col.append(i, value.Interface())
+		}
+	}
+	return &ColumnConverterError{
+		Op:   "AppendRow",
+		To:   string(col.chType),
+		From: fmt.Sprintf("%T", v),
+		Hint: "use column.VariantValue to set the variant explicitly",
+	}
+}
+
+func (col *Variant) Decode(decoder *binary.Decoder, rows int) error {
+	var discriminators UInt8
+	if err := discriminators.Decode(decoder, rows); err != nil {
+		return err
+	}
+	sizes := make([]int, len(col.columns))
+	for _, d := range discriminators {
+		switch {
+		case d == NullVariantDiscriminator:
+			col.offsets = append(col.offsets, 0)
+		case int(d) < len(col.columns):
+			col.offsets = append(col.offsets, sizes[d])
+			sizes[d]++
+		default:
+			return &Error{
+				ColumnType: string(col.chType),
+				Err:        fmt.Errorf("invalid discriminator %d", d),
+			}
+		}
+	}
+	col.discriminators = append(col.discriminators, discriminators...)
+	for i, c := range col.columns {
+		if err := c.Decode(decoder, sizes[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (col *Variant) Encode(encoder *binary.Encoder) error {
+	if err := encoder.Raw(col.discriminators); err != nil {
+		return err
+	}
+	for _, c := range col.columns {
+		if err := c.Encode(encoder); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (col *Variant) ReadStatePrefix(decoder *binary.Decoder) error {
+	mode, err := decoder.UInt64()
+	if err != nil {
+		return err
+	}
+	if mode != variantDiscriminatorsBasic {
+		return &Error{
+			ColumnType: string(col.chType),
+			Err:        fmt.Errorf("unsupported discriminators serialization mode %d", mode),
+		}
+	}
+	for _, c := range col.columns {
+		if serialize, ok := c.(CustomSerialization); ok {
+			if err := serialize.ReadStatePrefix(decoder); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (col *Variant) WriteStatePrefix(encoder *binary.Encoder) error {
+	if err := encoder.UInt64(variantDiscriminatorsBasic); err != nil {
+		return err
+	}
+	for _, c := range col.columns {
+		if serialize, ok := c.(CustomSerialization); ok {
+			if err := serialize.WriteStatePrefix(encoder); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// addVariant inserts the variant keeping the variants sorted by the type name and
+// returns its discriminator. The discriminators of the appended rows are shifted.
+func (col *Variant) addVariant(t Type, c Interface) int {
+	idx := sort.Search(len(col.types), func(i int) bool {
+		return col.types[i] >= t
+	})
+	col.types = append(col.types, "")
+	copy(col.types[idx+1:], col.types[idx:])
+	col.types[idx] = t
+	col.columns = append(col.columns, nil)
+	copy(col.columns[idx+1:], col.columns[idx:])
+	col.columns[idx] = c
+	for i, d := range col.discriminators {
+		if d != NullVariantDiscriminator && int(d) >= idx {
+			col.discriminators[i]++
+		}
+	}
+	return idx
+}
+
+func (col *Variant) variant(t Type) int {
+	for i, v := range col.types {
+		if v == t {
+			return i
+		}
+	}
+	return -1
+}
+
+func (col *Variant) append(idx int, v interface{}) error {
+	c := col.columns[idx]
+	// the numbers are converted to the numeric type of the variant only, e.g. not an int to a String
+	if value, scanType := reflect.ValueOf(v), c.ScanType(); scanType != nil && value.Type() != scanType && isNumericKind(value.Kind()) {
+		if !isNumericKind(scanType.Kind()) {
+			return &ColumnConverterError{
+				Op:   "AppendRow",
+				To:   string(col.types[idx]),
+				From: fmt.Sprintf("%T", v),
+			}
+		}
+		v = value.Convert(scanType).Interface()
+	}
+	offset := c.Rows()
+	if err := c.AppendRow(v); err != nil {
+		return err
+	}
+	col.discriminators, col.offsets = append(col.discriminators, uint8(idx)), append(col.offsets, offset)
+	return nil
+}
+
+func isNumericKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+func (col *Variant) appendNull() error {
+	col.discriminators, col.offsets = append(col.discriminators, NullVariantDiscriminator), append(col.offsets, 0)
+	return nil
+}
+
+var (
+	_ Interface           = (*Variant)(nil)
+	_ CustomSerialization = (*Variant)(nil)
+)
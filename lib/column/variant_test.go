@@ -0,0 +1,136 @@
+package column
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/ClickHouse/clickhouse-go/v2/lib/binary"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVariant(t *testing.T) {
+	var (
+		buffer  bytes.Buffer
+		decoder = binary.NewDecoder(&buffer)
+		encoder = binary.NewEncoder(&buffer)
+		chType  = Type("Variant(UInt64, String, Array(UInt8))")
+		str     = "B"
+	)
+	col, err := chType.Column()
+	if !assert.NoError(t, err) {
+		return
+	}
+	for _, v := range []interface{}{uint64(42), "A", nil, &str, []uint8{1, 2}, VariantValue{Type: "UInt64", Value: 7}} {
+		if !assert.NoError(t, col.AppendRow(v)) {
+			return
+		}
+	}
+	assert.Error(t, col.AppendRow(int32(1)))
+	assert.Error(t, col.AppendRow(VariantValue{Type: "Int32", Value: int32(1)}))
+	// a number is not converted to a String rune
+	assert.Error(t, col.AppendRow(VariantValue{Type: "String", Value: 65}))
+	if assert.NoError(t, col.(CustomSerialization).WriteStatePrefix(encoder)) && assert.NoError(t, col.Encode(encoder)) && assert.NoError(t, encoder.Flush()) {
+		assert.Equal(t, []byte{
+			0, 0, 0, 0, 0, 0, 0, 0, // discriminators mode
+			2, 1, 255, 1, 0, 2, // discriminators of Array(UInt8), String, UInt64
+			2, 0, 0, 0, 0, 0, 0, 0, // Array(UInt8) offsets
+			1, 2, // Array(UInt8) values
+			1, 'A', 1, 'B', // String
+			42, 0, 0, 0, 0, 0, 0, 0, 7, 0, 0, 0, 0, 0, 0, 0, // UInt64
+		}, buffer.Bytes())
+		col2, err := chType.Column()
+		if !assert.NoError(t, err) {
+			return
+		}
+		if assert.NoError(t, col2.(CustomSerialization).ReadStatePrefix(decoder)) && assert.NoError(t, col2.Decode(decoder, 6)) {
+			variant := col2.(*Variant)
+			assert.Equal(t, uint8(2), variant.Discriminator(0))
+			assert.Equal(t, uint8(NullVariantDiscriminator), variant.Discriminator(2))
+			var (
+				v1 interface{}
+				v2 string
+				v3 VariantValue
+				v4 = "not null"
+				v5 = &v4
+			)
+			if assert.NoError(t, col2.ScanRow(&v1, 0)) {
+				assert.Equal(t, uint64(42), v1)
+			}
+			if assert.NoError(t, col2.ScanRow(&v2, 3)) {
+				assert.Equal(t, "B", v2)
+			}
+			if assert.NoError(t, col2.ScanRow(&v3, 4)) {
+				assert.Equal(t, VariantValue{Type: "Array(UInt8)", Value: []uint8{1, 2}}, v3)
+			}
+			// NULL is scanned into the pointers, a string can not hold it
+			if assert.NoError(t, col2.ScanRow(&v1, 2)) && assert.NoError(t, col2.ScanRow(&v5, 2)) {
+				assert.Nil(t, v1)
+				assert.Nil(t, v5)
+			}
+			assert.Error(t, col2.ScanRow(&v4, 2))
+			assert.Error(t, col2.ScanRow(&v2, 0))
+		}
+	}
+}
+
+func TestDynamic(t *testing.T) {
+	var (
+		buffer  bytes.Buffer
+		decoder = binary.NewDecoder(&buffer)
+		encoder = binary.NewEncoder(&buffer)
+		now     = time.Unix(time.Now().Unix(), 0)
+		values  = []interface{}{
+			"A",
+			42,
+			nil,
+			now,
+			[]string{"B", "C"},
+			VariantValue{Type: "UInt8", Value: 1},
+			int64(43),
+		}
+	)
+	col, err := Type("Dynamic").Column()
+	if !assert.NoError(t, err) {
+		return
+	}
+	if _, err := col.Append(values); !assert.NoError(t, err) {
+		return
+	}
+	if assert.NoError(t, col.(CustomSerialization).WriteStatePrefix(encoder)) && assert.NoError(t, col.Encode(encoder)) {
+		col2, err := Type("Dynamic").Column()
+		if !assert.NoError(t, err) {
+			return
+		}
+		if assert.NoError(t, col2.(CustomSerialization).ReadStatePrefix(decoder)) && assert.NoError(t, col2.Decode(decoder, len(values))) {
+			dynamic := col2.(*Dynamic)
+			for i, expected := range []struct {
+				chType Type
+				value  interface{}
+			}{
+				{"String", "A"},
+				{"Int64", int64(42)},
+				{"", nil},
+				{"DateTime64(9)", now},
+				{"Array(String)", []string{"B", "C"}},
+				{"UInt8", uint8(1)},
+				{"Int64", int64(43)},
+			} {
+				var v VariantValue
+				if assert.NoError(t, col2.ScanRow(&v, i)) {
+					assert.Equal(t, expected.chType, dynamic.RowType(i))
+					assert.Equal(t, VariantValue{Type: expected.chType, Value: expected.value}, v)
+				}
+			}
+		}
+	}
+}
+
+func TestDynamicMaxTypes(t *testing.T) {
+	col, err := Type("Dynamic(max_types=1)").Column()
+	if assert.NoError(t, err) {
+		assert.NoError(t, col.AppendRow("A"))
+		assert.NoError(t, col.AppendRow("B"))
+		assert.Error(t, col.AppendRow(42))
+	}
+}
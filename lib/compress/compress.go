@@ -12,13 +12,40 @@ const (
 	NONE Method = 0x02
 	LZ4         = 0x82
 	ZSTD        = 0x90
+	// LZ4HC is a client-side method: blocks are compressed with the high compression LZ4 mode
+	// and sent with the LZ4 method byte, so the server decompresses them as regular LZ4 blocks.
+	LZ4HC = 0x83
 )
 
+func (m Method) String() string {
+	switch m {
+	case NONE:
+		return "none"
+	case LZ4:
+		return "lz4"
+	case LZ4HC:
+		return "lz4hc"
+	case ZSTD:
+		return "zstd"
+	}
+	return "unknown"
+}
+
+// header returns the method byte written to the header of a compressed block.
+func (m Method) header() byte {
+	if m == LZ4HC {
+		return LZ4
+	}
+	return byte(m)
+}
+
 const (
 	// ChecksumSize is 128bits for cityhash102 checksum
 	checksumSize = 16
 	// CompressHeader magic + compressed_size + uncompressed_size
 	compressHeaderSize = 1 + 4 + 4
 	headerSize         = checksumSize + compressHeaderSize
-	maxBlockSize       = 1 << 20
+	// DefaultBlockSize is the max size of the uncompressed data in a single block
+	DefaultBlockSize = 1 << 20
+	maxBlockSize     = DefaultBlockSize
 )
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io"
 
+	"github.com/klauspost/compress/zstd"
 	"github.com/pierrec/lz4/v4"
 )
 
@@ -17,12 +18,15 @@ func NewReader(r io.Reader) *Reader {
 	}
 }
 
+// Reader decompresses the blocks sent by the server. Each block carries its own method byte,
+// so blocks compressed with different methods can be read from the same stream.
 type Reader struct {
 	r      io.Reader
 	pos    int
 	data   []byte
 	zdata  []byte
 	header []byte
+	zstd   *zstd.Decoder
 }
 
 func (r *Reader) Read(p []byte) (int, error) {
@@ -54,10 +58,11 @@ func (r *Reader) readBlock() (err error) {
 		return
 	}
 	if n != len(r.header) {
-		return fmt.Errorf("LZ4 decompression header EOF")
+		return fmt.Errorf("decompression header EOF")
 	}
 	var (
-		compressedSize   = int(endian.Uint32(r.header[17:])) - 9
+		method           = Method(r.header[16])
+		compressedSize   = int(endian.Uint32(r.header[17:])) - compressHeaderSize
 		decompressedSize = int(endian.Uint32(r.header[21:]))
 	)
 	if compressedSize > cap(r.zdata) {
@@ -69,10 +74,10 @@ func (r *Reader) readBlock() (err error) {
 
 	r.data, r.zdata = r.data[:decompressedSize], r.zdata[:compressedSize]
 
-	switch r.header[16] {
-	case LZ4:
+	switch method {
+	case NONE, LZ4, ZSTD:
 	default:
-		return fmt.Errorf("unknown compression method: 0x%02x ", r.header[16])
+		return fmt.Errorf("unknown compression method: 0x%02x ", byte(method))
 	}
 	// @TODO checksum
 	if n, err = io.ReadFull(r.r, r.zdata); err != nil {
@@ -81,13 +86,39 @@ func (r *Reader) readBlock() (err error) {
 	if n != len(r.zdata) {
 		return fmt.Errorf("decompress read size not match")
 	}
-	if _, err = lz4.UncompressBlock(r.zdata, r.data); err != nil {
-		return
+	switch method {
+	case NONE:
+		if compressedSize != decompressedSize {
+			return fmt.Errorf("uncompressed block size not match")
+		}
+		copy(r.data, r.zdata)
+	case LZ4:
+		if _, err = lz4.UncompressBlock(r.zdata, r.data); err != nil {
+			return
+		}
+	case ZSTD:
+		if r.zstd == nil {
+			if r.zstd, err = zstd.NewReader(nil, zstd.WithDecoderConcurrency(1)); err != nil {
+				return
+			}
+		}
+		var data []byte
+		if data, err = r.zstd.DecodeAll(r.zdata, r.data[:0]); err != nil {
+			return
+		}
+		if len(data) != decompressedSize {
+			return fmt.Errorf("decompress size not match")
+		}
+		r.data = data
 	}
 	return nil
 }
 
 func (r *Reader) Close() error {
+	if r.zstd != nil {
+		r.zstd.Close()
+		r.zstd = nil
+	}
 	r.data = nil
 	r.zdata = nil
 	return nil
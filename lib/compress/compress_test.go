@@ -0,0 +1,96 @@
+package compress
+
+import (
+	"bytes"
+	"io"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompression(t *testing.T) {
+	data := make([]byte, 3*maxBlockSize+42)
+	for i := range data {
+		data[i] = byte(rand.Intn(8))
+	}
+	for _, method := range []Method{NONE, LZ4, LZ4HC, ZSTD} {
+		var (
+			buffer bytes.Buffer
+			writer = NewWriter(&buffer, method, 0, 0)
+			reader = NewReader(&buffer)
+		)
+		if _, err := writer.Write(data); !assert.NoError(t, err, method) {
+			return
+		}
+		if !assert.NoError(t, writer.Flush(), method) {
+			return
+		}
+		if method != NONE {
+			assert.Less(t, buffer.Len(), len(data), method)
+		}
+		actual := make([]byte, len(data))
+		if _, err := io.ReadFull(reader, actual); assert.NoError(t, err, method) {
+			assert.Equal(t, data, actual, method)
+		}
+		writer.Close()
+		reader.Close()
+	}
+}
+
+func TestBlockSize(t *testing.T) {
+	data := make([]byte, 5*maxBlockSize+42)
+	for i := range data {
+		data[i] = byte(rand.Intn(8))
+	}
+	for _, blockSize := range []int{1024, 4 * maxBlockSize} {
+		for _, method := range []Method{LZ4, LZ4HC, ZSTD} {
+			var (
+				buffer bytes.Buffer
+				writer = NewWriter(&buffer, method, 5, blockSize)
+				reader = NewReader(&buffer)
+			)
+			if _, err := writer.Write(data); !assert.NoError(t, err, method) {
+				return
+			}
+			if !assert.NoError(t, writer.Flush(), method) {
+				return
+			}
+			assert.Equal(t, method.header(), buffer.Bytes()[16], method)
+			actual := make([]byte, len(data))
+			if _, err := io.ReadFull(reader, actual); assert.NoError(t, err, method) {
+				assert.Equal(t, data, actual, "%s: block size %d", method, blockSize)
+			}
+		}
+	}
+}
+
+func TestMixedMethods(t *testing.T) {
+	var (
+		buffer bytes.Buffer
+		reader = NewReader(&buffer)
+	)
+	for _, method := range []Method{LZ4, ZSTD, NONE, LZ4HC} {
+		writer := NewWriter(&buffer, method, 3, 0)
+		if _, err := writer.Write([]byte(method.String())); !assert.NoError(t, err) {
+			return
+		}
+		if !assert.NoError(t, writer.Flush()) {
+			return
+		}
+	}
+	actual := make([]byte, len("lz4zstdnonelz4hc"))
+	if _, err := io.ReadFull(reader, actual); assert.NoError(t, err) {
+		assert.Equal(t, "lz4zstdnonelz4hc", string(actual))
+	}
+}
+
+func TestUnknownMethod(t *testing.T) {
+	var (
+		buffer bytes.Buffer
+		writer = NewWriter(&buffer, Method(0x42), 0, 0)
+	)
+	if _, err := writer.Write([]byte("data")); assert.NoError(t, err) {
+		assert.Error(t, writer.Flush())
+	}
+}
@@ -1,17 +1,27 @@
 package compress
 
 import (
+	"fmt"
 	"io"
 
 	"github.com/ClickHouse/clickhouse-go/v2/lib/cityhash102"
+	"github.com/klauspost/compress/zstd"
 	"github.com/pierrec/lz4/v4"
 )
 
-func NewWriter(wr io.Writer) *Writer {
+// NewWriter returns a writer that compresses the data with the given method in blocks of at most blockSize
+// bytes (DefaultBlockSize if blockSize <= 0). The level is used by LZ4HC (1-9) and ZSTD (1-22),
+// 0 means the default level of the method.
+func NewWriter(wr io.Writer, method Method, level, blockSize int) *Writer {
+	if blockSize <= 0 {
+		blockSize = DefaultBlockSize
+	}
 	return &Writer{
-		wr:    wr,
-		data:  make([]byte, maxBlockSize),
-		zdata: make([]byte, lz4.CompressBlockBound(maxBlockSize)+headerSize),
+		wr:     wr,
+		level:  level,
+		method: method,
+		data:   make([]byte, blockSize),
+		zdata:  make([]byte, lz4.CompressBlockBound(blockSize)+headerSize),
 	}
 }
 
@@ -20,7 +30,11 @@ type Writer struct {
 	pos        int
 	data       []byte
 	zdata      []byte
+	level      int
+	method     Method
 	compressor lz4.Compressor
+	lz4hc      *lz4.CompressorHC
+	zstd       *zstd.Encoder
 }
 
 func (w *Writer) Write(p []byte) (n int, err error) {
@@ -42,13 +56,43 @@ func (w *Writer) Flush() (err error) {
 	if w.pos == 0 {
 		return
 	}
-	compressedSize, err := w.compressor.CompressBlock(w.data[:w.pos], w.zdata[headerSize:])
-	if err != nil {
-		return err
+	var compressedSize int
+	switch w.method {
+	case NONE:
+		compressedSize = copy(w.zdata[headerSize:], w.data[:w.pos])
+	case LZ4:
+		if compressedSize, err = w.compressor.CompressBlock(w.data[:w.pos], w.zdata[headerSize:]); err != nil {
+			return err
+		}
+	case LZ4HC:
+		if w.lz4hc == nil {
+			level := lz4.Level9
+			if w.level > 0 && w.level < 9 {
+				level = lz4.CompressionLevel(1 << (8 + w.level))
+			}
+			w.lz4hc = &lz4.CompressorHC{Level: level}
+		}
+		if compressedSize, err = w.lz4hc.CompressBlock(w.data[:w.pos], w.zdata[headerSize:]); err != nil {
+			return err
+		}
+	case ZSTD:
+		if w.zstd == nil {
+			level := zstd.SpeedDefault
+			if w.level != 0 {
+				level = zstd.EncoderLevelFromZstd(w.level)
+			}
+			if w.zstd, err = zstd.NewWriter(nil, zstd.WithEncoderLevel(level), zstd.WithEncoderConcurrency(1)); err != nil {
+				return err
+			}
+		}
+		zdata := w.zstd.EncodeAll(w.data[:w.pos], w.zdata[:headerSize])
+		compressedSize, w.zdata = len(zdata)-headerSize, zdata[:cap(zdata)]
+	default:
+		return fmt.Errorf("unknown compression method: 0x%02x ", byte(w.method))
 	}
 	compressedSize += compressHeaderSize
 	// fill the header, compressed_size_32 + uncompressed_size_32
-	w.zdata[16] = LZ4
+	w.zdata[16] = w.method.header()
 	endian.PutUint32(w.zdata[17:], uint32(compressedSize))
 	endian.PutUint32(w.zdata[21:], uint32(w.pos))
 	// fill the checksum
@@ -68,6 +112,10 @@ func (w *Writer) Flush() (err error) {
 }
 
 func (w *Writer) Close() error {
+	if w.zstd != nil {
+		w.zstd.Close()
+		w.zstd = nil
+	}
 	w.data = nil
 	w.zdata = nil
 	return nil
@@ -2,11 +2,17 @@ package driver
 
 import (
 	"context"
+	"reflect"
+	"time"
 
 	"github.com/ClickHouse/clickhouse-go/v2/lib/proto"
 )
 
-type ServerVersion = proto.ServerHandshake
+type (
+	ServerVersion     = proto.ServerHandshake
+	TableStatus       = proto.TableStatus
+	ColumnDescription = proto.ColumnDescription
+)
 
 type (
 	NamedValue struct {
@@ -16,8 +22,32 @@ type (
 	Stats struct {
 		MaxOpenConns int
 		MaxIdleConns int
-		Open         int
+		Open         int // the open connections, in use and idle
 		Idle         int
+		InUse        int
+		// WaitCount and WaitDuration are the acquires which waited for a connection and the total time they waited.
+		WaitCount         int64
+		WaitDuration      time.Duration
+		AcquireTimeouts   int64
+		MaxIdleClosed     int64 // the connections closed because the idle pool was full
+		MaxIdleTimeClosed int64
+		MaxLifetimeClosed int64
+		ErrorClosed       int64 // the broken connections closed by the pool
+		SessionClosed     int64 // the connections closed on Session.Release to drop the session state
+		BytesSent         int64
+		BytesReceived     int64
+		Hosts             []HostStats
+	}
+	HostStats struct {
+		Addr     string
+		Priority int
+		Open     int // the open connections of the address
+		// Healthy is false while the address is in backoff after a failed dial, it is dialed after the healthy ones.
+		Healthy      bool
+		Failures     int   // the consecutive failed dials
+		DialFailures int64 // all the failed dials
+		DownUntil    time.Time
+		LastError    error
 	}
 )
 
@@ -29,10 +59,28 @@ type (
 		QueryRow(ctx context.Context, query string, args ...interface{}) Row
 		PrepareBatch(ctx context.Context, query string) (Batch, error)
 		Exec(ctx context.Context, query string, args ...interface{}) error
+		AsyncInsert(ctx context.Context, query string, wait bool, args ...interface{}) error
 		Ping(context.Context) error
+		TablesStatus(ctx context.Context, tables ...string) ([]TableStatus, error)
+		// Acquire pins a connection of the pool to a session until Release. The session state is not reset:
+		// a connection whose state was changed by the session is closed on Release instead of going back to the pool.
+		Acquire(ctx context.Context) (Session, error)
 		Stats() Stats
 		Close() error
 	}
+	// Session runs the queries on the same connection, the changes of the session state (SET, USE,
+	// temporary tables) are visible to the next queries of the session. Once the connection is broken,
+	// e.g. by a cancelled context, the calls of the session return its error.
+	Session interface {
+		Select(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+		Query(ctx context.Context, query string, args ...interface{}) (Rows, error)
+		QueryRow(ctx context.Context, query string, args ...interface{}) Row
+		PrepareBatch(ctx context.Context, query string) (Batch, error)
+		Exec(ctx context.Context, query string, args ...interface{}) error
+		AsyncInsert(ctx context.Context, query string, wait bool, args ...interface{}) error
+		Ping(context.Context) error
+		Release() error
+	}
 	Row interface {
 		Err() error
 		Scan(dest ...interface{}) error
@@ -40,18 +88,45 @@ type (
 	}
 	Rows interface {
 		Next() bool
+		NextBlock() bool
+		Block() Block
 		Scan(dest ...interface{}) error
 		ScanStruct(dest interface{}) error
 		Totals(dest ...interface{}) error
+		Extremes(dest ...interface{}) error
 		Columns() []string
+		ColumnTypes() []ColumnType
 		Close() error
 		Err() error
 	}
+	// ColumnType describes a column of the result, the elements are the types a composite type is made of
+	// (the element of Array, Nullable and LowCardinality, the keys and the values of Map, the elements of Tuple ...).
+	ColumnType interface {
+		Name() string
+		DatabaseTypeName() string
+		ScanType() reflect.Type
+		Nullable() bool
+		PrecisionScale() (precision, scale int64, ok bool)
+		Timezone() *time.Location
+		EnumValues() map[string]int
+		Elements() []ColumnType
+	}
+	// Block is a block of the result, Column returns the values of a column as a slice ([]int64, []string, []time.Time ...).
+	Block interface {
+		Rows() int
+		Columns() []string
+		Column(idx int) (interface{}, error)
+	}
 	Batch interface {
 		Append(v ...interface{}) error
 		AppendStruct(v interface{}) error
 		Column(int) BatchColumn
+		TableColumns() []ColumnDescription
+		Flush() error
 		Send() error
+		Abort() error
+		Reset() error
+		Clone() (Batch, error)
 	}
 	BatchColumn interface {
 		Append(interface{}) error
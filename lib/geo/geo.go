@@ -0,0 +1,19 @@
+// Package geo contains the Go representation of the ClickHouse geo types.
+// https://clickhouse.com/docs/en/sql-reference/data-types/geo/
+package geo
+
+// Point is stored as a Tuple(Float64, Float64).
+type Point struct {
+	X float64
+	Y float64
+}
+
+type (
+	// Ring is a simple polygon without holes stored as an array of points: Array(Point).
+	Ring []Point
+	// Polygon is a polygon with holes stored as an array of rings: Array(Ring).
+	// The first element is the outer polygon and the following elements are the holes.
+	Polygon []Ring
+	// MultiPolygon consists of multiple polygons and is stored as an array of polygons: Array(Polygon).
+	MultiPolygon []Polygon
+)
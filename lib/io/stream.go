@@ -2,6 +2,7 @@ package io
 
 import (
 	"bufio"
+	"encoding/binary"
 	"io"
 
 	"github.com/ClickHouse/clickhouse-go/v2/lib/compress"
@@ -12,19 +13,24 @@ const (
 	maxWriterSize = 1 << 20
 )
 
-func NewStream(rw io.ReadWriter) *Stream {
+func NewStream(rw io.ReadWriter, method compress.Method, level, blockSize int) *Stream {
 	stream := Stream{
-		r: bufio.NewReaderSize(rw, maxReaderSize),
-		w: bufio.NewWriterSize(rw, maxWriterSize),
+		chunked: chunked{
+			r: rw,
+			w: rw,
+		},
 	}
+	stream.r = bufio.NewReaderSize(&stream.chunked, maxReaderSize)
+	stream.w = bufio.NewWriterSize(&stream.chunked, maxWriterSize)
 	stream.compress.r = compress.NewReader(stream.r)
-	stream.compress.w = compress.NewWriter(stream.w)
+	stream.compress.w = compress.NewWriter(stream.w, method, level, blockSize)
 	return &stream
 }
 
 type Stream struct {
 	r        *bufio.Reader
 	w        *bufio.Writer
+	chunked  chunked
 	compress struct {
 		enable bool
 		r      *compress.Reader
@@ -36,6 +42,22 @@ func (s *Stream) Compress(v bool) {
 	s.compress.enable = v
 }
 
+// Chunked enables the chunked framing of the sent and of the received packets.
+func (s *Stream) Chunked(send, recv bool) {
+	s.chunked.send, s.chunked.recv = send, recv
+}
+
+// EndPacket marks the end of the sent packet, the packets are delimited only if the framing is chunked.
+func (s *Stream) EndPacket() error {
+	if !s.chunked.send {
+		return nil
+	}
+	if err := s.Flush(); err != nil {
+		return err
+	}
+	return s.chunked.endPacket()
+}
+
 func (s *Stream) Read(p []byte) (int, error) {
 	if s.compress.enable {
 		return io.ReadFull(s.compress.r, p)
@@ -63,3 +85,50 @@ func (s *Stream) Close() error {
 	s.compress.w.Close()
 	return nil
 }
+
+// chunked frames the data: every chunk is preceded by its UInt32 size and
+// the chunks of a packet are followed by an empty chunk.
+type chunked struct {
+	r      io.Reader
+	w      io.Writer
+	send   bool
+	recv   bool
+	left   uint32
+	header [4]byte
+}
+
+func (c *chunked) Read(p []byte) (int, error) {
+	if !c.recv {
+		return c.r.Read(p)
+	}
+	for c.left == 0 {
+		if _, err := io.ReadFull(c.r, c.header[:]); err != nil {
+			return 0, err
+		}
+		// an empty chunk is the end of the packet
+		c.left = binary.LittleEndian.Uint32(c.header[:])
+	}
+	if len(p) > int(c.left) {
+		p = p[:c.left]
+	}
+	n, err := c.r.Read(p)
+	c.left -= uint32(n)
+	return n, err
+}
+
+func (c *chunked) Write(p []byte) (int, error) {
+	if !c.send || len(p) == 0 {
+		return c.w.Write(p)
+	}
+	binary.LittleEndian.PutUint32(c.header[:], uint32(len(p)))
+	if _, err := c.w.Write(c.header[:]); err != nil {
+		return 0, err
+	}
+	return c.w.Write(p)
+}
+
+func (c *chunked) endPacket() error {
+	binary.LittleEndian.PutUint32(c.header[:], 0)
+	_, err := c.w.Write(c.header[:])
+	return err
+}
@@ -0,0 +1,61 @@
+package io
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ClickHouse/clickhouse-go/v2/lib/compress"
+	"github.com/stretchr/testify/assert"
+)
+
+type readWriter struct {
+	bytes.Reader
+	bytes.Buffer
+}
+
+func (rw *readWriter) Read(p []byte) (int, error) {
+	return rw.Reader.Read(p)
+}
+
+func (rw *readWriter) Write(p []byte) (int, error) {
+	return rw.Buffer.Write(p)
+}
+
+func TestStreamChunkedWrite(t *testing.T) {
+	for _, asset := range []struct {
+		chunked  bool
+		expected []byte
+	}{
+		{chunked: false, expected: []byte{1, 2, 3, 4}},
+		{chunked: true, expected: []byte{3, 0, 0, 0, 1, 2, 3, 0, 0, 0, 0, 1, 0, 0, 0, 4, 0, 0, 0, 0}},
+	} {
+		var (
+			rw     readWriter
+			stream = NewStream(&rw, compress.NONE, 0, 0)
+		)
+		stream.Chunked(asset.chunked, false)
+		if _, err := stream.Write([]byte{1, 2, 3}); assert.NoError(t, err) {
+			if assert.NoError(t, stream.EndPacket()) {
+				if _, err := stream.Write([]byte{4}); assert.NoError(t, err) {
+					if assert.NoError(t, stream.EndPacket()) && assert.NoError(t, stream.Flush()) {
+						assert.Equal(t, asset.expected, rw.Buffer.Bytes())
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestStreamChunkedRead(t *testing.T) {
+	var rw readWriter
+	rw.Reader.Reset([]byte{
+		2, 0, 0, 0, 1, 2, 1, 0, 0, 0, 3, 0, 0, 0, 0, // packet of 2 chunks
+		1, 0, 0, 0, 4, 0, 0, 0, 0,
+	})
+	stream := NewStream(&rw, compress.NONE, 0, 0)
+	stream.Chunked(false, true)
+	data := make([]byte, 4)
+	if _, err := stream.Read(data); assert.NoError(t, err) {
+		assert.Equal(t, []byte{1, 2, 3, 4}, data)
+	}
+}
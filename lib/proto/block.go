@@ -3,6 +3,7 @@ package proto
 import (
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/ClickHouse/clickhouse-go/v2/lib/binary"
 	"github.com/ClickHouse/clickhouse-go/v2/lib/column"
@@ -50,6 +51,53 @@ func (b *Block) Append(v ...interface{}) (err error) {
 	return nil
 }
 
+// Reset replaces the columns by empty columns of the same types.
+func (b *Block) Reset() error {
+	for i, c := range b.Columns {
+		column, err := c.Type().Column()
+		if err != nil {
+			return err
+		}
+		b.Columns[i] = column
+	}
+	return nil
+}
+
+// validateNested checks that the flattened columns of a Nested data structure (n.a, n.b)
+// have arrays of the same size in every row.
+func (b *Block) validateNested() error {
+	nested := make(map[string]int)
+	for i, c := range b.Columns {
+		array, ok := c.(*column.Array)
+		if !ok {
+			continue
+		}
+		n := strings.Index(b.names[i], ".")
+		if n <= 0 {
+			continue
+		}
+		first, found := nested[b.names[i][:n]]
+		if !found {
+			nested[b.names[i][:n]] = i
+			continue
+		}
+		var (
+			offsets  = array.Offsets()
+			expected = b.Columns[first].(*column.Array).Offsets()
+		)
+		for row := range offsets {
+			if offsets[row] != expected[row] {
+				return &BlockError{
+					Op:         "Encode",
+					Err:        fmt.Errorf("mismatched array sizes of nested columns %s and %s in row %d", b.names[first], b.names[i], row),
+					ColumnName: b.names[i],
+				}
+			}
+		}
+	}
+	return nil
+}
+
 func (b *Block) ColumnsNames() []string {
 	return b.names
 }
@@ -70,6 +118,9 @@ func (b *Block) Encode(encoder *binary.Encoder, revision uint64) error {
 			}
 		}
 	}
+	if err := b.validateNested(); err != nil {
+		return err
+	}
 	if err := encoder.Uvarint(uint64(len(b.Columns))); err != nil {
 		return err
 	}
@@ -83,6 +134,11 @@ func (b *Block) Encode(encoder *binary.Encoder, revision uint64) error {
 		if err := encoder.String(string(c.Type())); err != nil {
 			return err
 		}
+		if revision >= DBMS_MIN_REVISION_WITH_CUSTOM_SERIALIZATION {
+			if err := encoder.Bool(false); err != nil { // has_custom
+				return err
+			}
+		}
 		if serialize, ok := c.(column.CustomSerialization); ok {
 			if err := serialize.WriteStatePrefix(encoder); err != nil {
 				return &BlockError{
@@ -139,7 +195,32 @@ func (b *Block) Decode(decoder *binary.Decoder, revision uint64) (err error) {
 		if err != nil {
 			return err
 		}
-		if numRows != 0 {
+		var sparse bool
+		if revision >= DBMS_MIN_REVISION_WITH_CUSTOM_SERIALIZATION {
+			hasCustom, err := decoder.Bool()
+			if err != nil {
+				return err
+			}
+			if hasCustom {
+				if sparse, err = column.ReadSerializationKind(decoder, c); err != nil {
+					return &BlockError{
+						Op:         "Decode",
+						Err:        err,
+						ColumnName: columnName,
+					}
+				}
+			}
+		}
+		switch {
+		case numRows != 0 && sparse:
+			if err := column.DecodeSparse(decoder, c, int(numRows)); err != nil {
+				return &BlockError{
+					Op:         "Decode",
+					Err:        err,
+					ColumnName: columnName,
+				}
+			}
+		case numRows != 0:
 			if serialize, ok := c.(column.CustomSerialization); ok {
 				if err := serialize.ReadStatePrefix(decoder); err != nil {
 					return &BlockError{
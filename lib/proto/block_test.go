@@ -0,0 +1,124 @@
+package proto
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ClickHouse/clickhouse-go/v2/lib/binary"
+	"github.com/ClickHouse/clickhouse-go/v2/lib/column"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlockNestedSizes(t *testing.T) {
+	for _, asset := range []struct {
+		values []interface{}
+		valid  bool
+	}{
+		{values: []interface{}{uint64(1), []uint8{1, 2}, []string{"a", "b"}, []string{"c"}}, valid: true},
+		{values: []interface{}{uint64(1), []uint8{1, 2}, []string{"a"}, []string{"c"}}, valid: false},
+	} {
+		var block Block
+		for _, c := range []struct{ name, chType string }{
+			{"id", "UInt64"},
+			{"n.a", "Array(UInt8)"},
+			{"n.b", "Array(String)"},
+			{"tags", "Array(String)"},
+		} {
+			if !assert.NoError(t, block.AddColumn(c.name, column.Type(c.chType))) {
+				return
+			}
+		}
+		if assert.NoError(t, block.Append(asset.values...)) {
+			var buffer bytes.Buffer
+			err := block.Encode(binary.NewEncoder(&buffer), 0)
+			if asset.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		}
+	}
+}
+
+func TestBlockCustomSerialization(t *testing.T) {
+	var block Block
+	if assert.NoError(t, block.AddColumn("x", "UInt8")) && assert.NoError(t, block.Append(uint8(1))) {
+		header := []byte{
+			1, 0, 2, 0xff, 0xff, 0xff, 0xff, 0, // block info
+			1, 1, // columns, rows
+			1, 'x',
+			5, 'U', 'I', 'n', 't', '8',
+		}
+		for _, asset := range []struct {
+			revision uint64
+			expected []byte
+		}{
+			{revision: DBMS_MIN_REVISION_WITH_PARALLEL_REPLICAS, expected: append(append([]byte{}, header...), 1)},
+			{revision: DBMS_MIN_REVISION_WITH_CUSTOM_SERIALIZATION, expected: append(append([]byte{}, header...), 0 /* has_custom */, 1)},
+		} {
+			var (
+				buffer  bytes.Buffer
+				encoder = binary.NewEncoder(&buffer)
+			)
+			if assert.NoError(t, block.Encode(encoder, asset.revision)) {
+				assert.Equal(t, asset.expected, buffer.Bytes())
+			}
+		}
+	}
+}
+
+func TestBlockSparseDecode(t *testing.T) {
+	data := []byte{
+		1, 0, 2, 0xff, 0xff, 0xff, 0xff, 0, // block info
+		3, 5, // columns, rows
+		// x UInt64: 0, 7, 0, 9, 0
+		1, 'x',
+		6, 'U', 'I', 'n', 't', '6', '4',
+		1, 1, // has_custom, sparse
+		1, 1, 0x81, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x40, // offsets
+		7, 0, 0, 0, 0, 0, 0, 0,
+		9, 0, 0, 0, 0, 0, 0, 0,
+		// s Nullable(String): NULL, NULL, NULL, NULL, 'a'
+		1, 's',
+		16, 'N', 'u', 'l', 'l', 'a', 'b', 'l', 'e', '(', 'S', 't', 'r', 'i', 'n', 'g', ')',
+		1, 1, // has_custom, sparse
+		4, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x40, // offsets
+		0, 1, 'a',
+		// d UInt8: 1, 2, 3, 4, 5
+		1, 'd',
+		5, 'U', 'I', 'n', 't', '8',
+		0, // has_custom
+		1, 2, 3, 4, 5,
+	}
+	var (
+		block  Block
+		reader = bytes.NewReader(data)
+	)
+	if assert.NoError(t, block.Decode(binary.NewDecoder(reader), DBMS_MIN_REVISION_WITH_SPARSE_SERIALIZATION)) {
+		assert.Equal(t, 0, reader.Len())
+		assert.Equal(t, 5, block.Rows())
+		for i, expected := range []interface{}{uint64(0), uint64(7), uint64(0), uint64(9), uint64(0)} {
+			assert.Equal(t, expected, block.Columns[0].Row(i, false))
+		}
+		for i := 0; i < 4; i++ {
+			assert.Nil(t, block.Columns[1].Row(i, false))
+		}
+		var s *string
+		if assert.NoError(t, block.Columns[1].ScanRow(&s, 4)) && assert.NotNil(t, s) {
+			assert.Equal(t, "a", *s)
+		}
+		assert.Equal(t, uint8(5), block.Columns[2].Row(4, false))
+	}
+}
+
+func TestBlockReset(t *testing.T) {
+	var block Block
+	if assert.NoError(t, block.AddColumn("id", "UInt64")) && assert.NoError(t, block.AddColumn("tags", "Array(String)")) {
+		if assert.NoError(t, block.Append(uint64(1), []string{"a"})) && assert.NoError(t, block.Reset()) {
+			if assert.Equal(t, 0, block.Rows()) && assert.Len(t, block.Columns, 2) {
+				assert.Equal(t, []string{"id", "tags"}, block.ColumnsNames())
+				assert.Equal(t, column.Type("Array(String)"), block.Columns[1].Type())
+			}
+		}
+	}
+}
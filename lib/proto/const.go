@@ -2,28 +2,46 @@ package proto
 
 // see https://github.com/ClickHouse/ClickHouse/blob/master/src/Core/Protocol.h
 const (
-	DBMS_MIN_REVISION_WITH_CLIENT_INFO                        = 54032
-	DBMS_MIN_REVISION_WITH_SERVER_TIMEZONE                    = 54058
-	DBMS_MIN_REVISION_WITH_QUOTA_KEY_IN_CLIENT_INFO           = 54060
-	DBMS_MIN_REVISION_WITH_SERVER_DISPLAY_NAME                = 54372
-	DBMS_MIN_REVISION_WITH_VERSION_PATCH                      = 54401
-	DBMS_MIN_REVISION_WITH_CLIENT_WRITE_INFO                  = 54420
-	DBMS_MIN_REVISION_WITH_SETTINGS_SERIALIZED_AS_STRINGS     = 54429
-	DBMS_MIN_REVISION_WITH_INTERSERVER_SECRET                 = 54441
-	DBMS_MIN_REVISION_WITH_OPENTELEMETRY                      = 54442
-	DBMS_MIN_PROTOCOL_VERSION_WITH_DISTRIBUTED_DEPTH          = 54448
-	DBMS_MIN_PROTOCOL_VERSION_WITH_INITIAL_QUERY_START_TIME   = 54449
-	DBMS_MIN_PROTOCOL_VERSION_WITH_INCREMENTAL_PROFILE_EVENTS = 54451
-	DBMS_MIN_REVISION_WITH_PARALLEL_REPLICAS                  = 54453
-	DBMS_TCP_PROTOCOL_VERSION                                 = DBMS_MIN_REVISION_WITH_PARALLEL_REPLICAS
+	DBMS_MIN_REVISION_WITH_CLIENT_INFO                           = 54032
+	DBMS_MIN_REVISION_WITH_SERVER_TIMEZONE                       = 54058
+	DBMS_MIN_REVISION_WITH_QUOTA_KEY_IN_CLIENT_INFO              = 54060
+	DBMS_MIN_REVISION_WITH_SERVER_DISPLAY_NAME                   = 54372
+	DBMS_MIN_REVISION_WITH_VERSION_PATCH                         = 54401
+	DBMS_MIN_REVISION_WITH_CLIENT_WRITE_INFO                     = 54420
+	DBMS_MIN_REVISION_WITH_SETTINGS_SERIALIZED_AS_STRINGS        = 54429
+	DBMS_MIN_REVISION_WITH_INTERSERVER_SECRET                    = 54441
+	DBMS_MIN_REVISION_WITH_OPENTELEMETRY                         = 54442
+	DBMS_MIN_PROTOCOL_VERSION_WITH_DISTRIBUTED_DEPTH             = 54448
+	DBMS_MIN_PROTOCOL_VERSION_WITH_INITIAL_QUERY_START_TIME      = 54449
+	DBMS_MIN_PROTOCOL_VERSION_WITH_INCREMENTAL_PROFILE_EVENTS    = 54451
+	DBMS_MIN_REVISION_WITH_PARALLEL_REPLICAS                     = 54453
+	DBMS_MIN_REVISION_WITH_CUSTOM_SERIALIZATION                  = 54454
+	DBMS_MIN_PROTOCOL_VERSION_WITH_PROFILE_EVENTS_IN_INSERT      = 54456
+	DBMS_MIN_PROTOCOL_VERSION_WITH_ADDENDUM                      = 54458
+	DBMS_MIN_PROTOCOL_VERSION_WITH_QUOTA_KEY                     = 54458
+	DBMS_MIN_PROTOCOL_VERSION_WITH_PARAMETERS                    = 54459
+	DBMS_MIN_PROTOCOL_VERSION_WITH_SERVER_QUERY_TIME_IN_PROGRESS = 54460
+	DBMS_MIN_PROTOCOL_VERSION_WITH_PASSWORD_COMPLEXITY_RULES     = 54461
+	DBMS_MIN_REVISION_WITH_INTERSERVER_SECRET_V2                 = 54462
+	DBMS_MIN_PROTOCOL_VERSION_WITH_TOTAL_BYTES_IN_PROGRESS       = 54463
+	DBMS_MIN_PROTOCOL_VERSION_WITH_TIMEZONE_UPDATES              = 54464
+	DBMS_MIN_REVISION_WITH_SPARSE_SERIALIZATION                  = 54465
+	DBMS_MIN_REVISION_WITH_SSH_AUTHENTICATION                    = 54466
+	DBMS_MIN_REVISION_WITH_TABLE_READ_ONLY_CHECK                 = 54467
+	DBMS_MIN_REVISION_WITH_SYSTEM_KEYWORDS_TABLE                 = 54468
+	DBMS_MIN_REVISION_WITH_ROWS_BEFORE_AGGREGATION               = 54469
+	DBMS_MIN_PROTOCOL_VERSION_WITH_CHUNKED_PACKETS               = 54470
+	DBMS_TCP_PROTOCOL_VERSION                                    = DBMS_MIN_PROTOCOL_VERSION_WITH_CHUNKED_PACKETS
 )
 
 const (
-	ClientHello  = 0
-	ClientQuery  = 1
-	ClientData   = 2
-	ClientCancel = 3
-	ClientPing   = 4
+	ClientHello               = 0
+	ClientQuery               = 1
+	ClientData                = 2
+	ClientCancel              = 3
+	ClientPing                = 4
+	ClientTablesStatusRequest = 5
+	ClientReadTaskResponse    = 9
 )
 
 const (
@@ -57,5 +75,7 @@ const (
 	ServerPartUUIDs           = 12
 	ServerReadTaskRequest     = 13
 	ServerProfileEvents       = 14
-	ServerTreeReadTaskRequest = 15
+	ServerTreeReadTaskRequest = 15 // MergeTreeAllRangesAnnouncement since the parallel replicas revision
+	ServerMergeTreeReadTask   = 16
+	ServerTimezoneUpdate      = 17
 )
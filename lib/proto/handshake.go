@@ -2,6 +2,7 @@ package proto
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/ClickHouse/clickhouse-go/v2/lib/binary"
@@ -45,6 +46,16 @@ type ServerHandshake struct {
 		Patch uint64
 	}
 	Timezone *time.Location
+	// chunked packets capabilities of the server: chunked, notchunked, chunked_optional or notchunked_optional
+	ChunkedSend             string
+	ChunkedRecv             string
+	PasswordComplexityRules []PasswordComplexityRule
+	Nonce                   uint64
+}
+
+type PasswordComplexityRule struct {
+	Pattern string
+	Message string
 }
 
 func (srv *ServerHandshake) Decode(decoder *binary.Decoder) (err error) {
@@ -60,7 +71,12 @@ func (srv *ServerHandshake) Decode(decoder *binary.Decoder) (err error) {
 	if srv.Revision, err = decoder.Uvarint(); err != nil {
 		return fmt.Errorf("could not read server revision: %v", err)
 	}
-	if srv.Revision >= DBMS_MIN_REVISION_WITH_SERVER_TIMEZONE {
+	// the server sends the fields supported by both sides
+	revision := srv.Revision
+	if revision > ClientTCPProtocolVersion {
+		revision = ClientTCPProtocolVersion
+	}
+	if revision >= DBMS_MIN_REVISION_WITH_SERVER_TIMEZONE {
 		name, err := decoder.String()
 		if err != nil {
 			return fmt.Errorf("could not read server timezone: %v", err)
@@ -69,18 +85,47 @@ func (srv *ServerHandshake) Decode(decoder *binary.Decoder) (err error) {
 			return fmt.Errorf("could not load time location: %v", err)
 		}
 	}
-	if srv.Revision >= DBMS_MIN_REVISION_WITH_SERVER_DISPLAY_NAME {
+	if revision >= DBMS_MIN_REVISION_WITH_SERVER_DISPLAY_NAME {
 		if srv.DisplayName, err = decoder.String(); err != nil {
 			return fmt.Errorf("could not read server display name: %v", err)
 		}
 	}
-	if srv.Revision >= DBMS_MIN_REVISION_WITH_VERSION_PATCH {
+	if revision >= DBMS_MIN_REVISION_WITH_VERSION_PATCH {
 		if srv.Version.Patch, err = decoder.Uvarint(); err != nil {
 			return fmt.Errorf("could not read server patch: %v", err)
 		}
 	} else {
 		srv.Version.Patch = srv.Revision
 	}
+	if revision >= DBMS_MIN_PROTOCOL_VERSION_WITH_CHUNKED_PACKETS {
+		if srv.ChunkedSend, err = decoder.String(); err != nil {
+			return fmt.Errorf("could not read server chunked send capability: %v", err)
+		}
+		if srv.ChunkedRecv, err = decoder.String(); err != nil {
+			return fmt.Errorf("could not read server chunked recv capability: %v", err)
+		}
+	}
+	if revision >= DBMS_MIN_PROTOCOL_VERSION_WITH_PASSWORD_COMPLEXITY_RULES {
+		rules, err := decoder.Uvarint()
+		if err != nil {
+			return fmt.Errorf("could not read password complexity rules: %v", err)
+		}
+		for i := 0; i < int(rules); i++ {
+			var rule PasswordComplexityRule
+			if rule.Pattern, err = decoder.String(); err != nil {
+				return fmt.Errorf("could not read password complexity rule: %v", err)
+			}
+			if rule.Message, err = decoder.String(); err != nil {
+				return fmt.Errorf("could not read password complexity rule: %v", err)
+			}
+			srv.PasswordComplexityRules = append(srv.PasswordComplexityRules, rule)
+		}
+	}
+	if revision >= DBMS_MIN_REVISION_WITH_INTERSERVER_SECRET_V2 {
+		if srv.Nonce, err = decoder.UInt64(); err != nil {
+			return fmt.Errorf("could not read server nonce: %v", err)
+		}
+	}
 	return nil
 }
 
@@ -93,3 +138,57 @@ func (srv ServerHandshake) String() string {
 		srv.Timezone,
 	)
 }
+
+const (
+	Chunked            = "chunked"
+	NotChunked         = "notchunked"
+	ChunkedOptional    = "chunked_optional"
+	NotChunkedOptional = "notchunked_optional"
+)
+
+// NegotiateChunked returns whether the packets of one direction are chunked.
+// srv is the capability of the server for the direction, client is the one of the client.
+func NegotiateChunked(srv, client string) (bool, error) {
+	var (
+		chunkedSrv     = strings.HasPrefix(srv, Chunked)
+		optionalSrv    = strings.HasSuffix(srv, "_optional")
+		chunkedClient  = strings.HasPrefix(client, Chunked)
+		optionalClient = strings.HasSuffix(client, "_optional")
+	)
+	switch {
+	case optionalSrv:
+		return chunkedClient, nil
+	case optionalClient:
+		return chunkedSrv, nil
+	case chunkedSrv != chunkedClient:
+		return false, fmt.Errorf("incompatible protocol: client uses %s packets, server requires %s", client, srv)
+	}
+	return chunkedSrv, nil
+}
+
+// ClientAddendum is sent after the server hello.
+type ClientAddendum struct {
+	QuotaKey    string
+	ChunkedSend bool
+	ChunkedRecv bool
+}
+
+func (a *ClientAddendum) Encode(encoder *binary.Encoder, revision uint64) error {
+	if revision >= DBMS_MIN_PROTOCOL_VERSION_WITH_QUOTA_KEY {
+		if err := encoder.String(a.QuotaKey); err != nil {
+			return err
+		}
+	}
+	if revision >= DBMS_MIN_PROTOCOL_VERSION_WITH_CHUNKED_PACKETS {
+		for _, chunked := range []bool{a.ChunkedSend, a.ChunkedRecv} {
+			v := NotChunked
+			if chunked {
+				v = Chunked
+			}
+			if err := encoder.String(v); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package proto
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ClickHouse/clickhouse-go/v2/lib/binary"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServerHandshakeDecode(t *testing.T) {
+	var (
+		hello = []byte{
+			2, 'C', 'H', // name
+			24,   // major
+			8,    // minor
+			0xff, // revision (replaced)
+			0xff,
+			0xff,
+			3, 'U', 'T', 'C', // timezone
+			2, 'c', 'h', // display name
+			5, // patch
+		}
+		chunked = []byte{
+			19, 'n', 'o', 't', 'c', 'h', 'u', 'n', 'k', 'e', 'd', '_', 'o', 'p', 't', 'i', 'o', 'n', 'a', 'l',
+			7, 'c', 'h', 'u', 'n', 'k', 'e', 'd',
+		}
+		rules = []byte{
+			1,
+			8, '^', '.', '{', '1', '2', ',', '}', '$',
+			5, 'S', 'h', 'o', 'r', 't',
+		}
+		nonce = []byte{1, 0, 0, 0, 0, 0, 0, 0}
+	)
+	fixture := func(revision []byte, fields ...[]byte) []byte {
+		data := append([]byte{}, hello...)
+		copy(data[5:8], revision)
+		for _, f := range fields {
+			data = append(data, f...)
+		}
+		return data
+	}
+	for _, asset := range []struct {
+		name     string
+		data     []byte
+		revision uint64
+		chunked  bool
+	}{
+		{name: "54453", data: fixture([]byte{0xb5, 0xa9, 0x03}), revision: 54453},
+		{name: "54470", data: fixture([]byte{0xc6, 0xa9, 0x03}, chunked, rules, nonce), revision: 54470, chunked: true},
+		// a newer server sends the fields supported by the client only
+		{name: "54480", data: fixture([]byte{0xd0, 0xa9, 0x03}, chunked, rules, nonce), revision: 54480, chunked: true},
+	} {
+		t.Run(asset.name, func(t *testing.T) {
+			var (
+				srv    ServerHandshake
+				reader = bytes.NewReader(asset.data)
+			)
+			if assert.NoError(t, srv.Decode(binary.NewDecoder(reader))) {
+				assert.Equal(t, 0, reader.Len())
+				assert.Equal(t, "CH", srv.Name)
+				assert.Equal(t, asset.revision, srv.Revision)
+				assert.Equal(t, "UTC", srv.Timezone.String())
+				assert.Equal(t, "ch", srv.DisplayName)
+				assert.Equal(t, uint64(5), srv.Version.Patch)
+				if asset.chunked {
+					assert.Equal(t, NotChunkedOptional, srv.ChunkedSend)
+					assert.Equal(t, Chunked, srv.ChunkedRecv)
+					assert.Equal(t, []PasswordComplexityRule{{Pattern: "^.{12,}$", Message: "Short"}}, srv.PasswordComplexityRules)
+					assert.Equal(t, uint64(1), srv.Nonce)
+				}
+			}
+		})
+	}
+}
+
+func TestClientAddendumEncode(t *testing.T) {
+	addendum := ClientAddendum{
+		QuotaKey:    "key",
+		ChunkedSend: true,
+	}
+	for _, asset := range []struct {
+		revision uint64
+		expected []byte
+	}{
+		{revision: DBMS_MIN_REVISION_WITH_PARALLEL_REPLICAS, expected: []byte{}},
+		{revision: DBMS_MIN_PROTOCOL_VERSION_WITH_QUOTA_KEY, expected: []byte{3, 'k', 'e', 'y'}},
+		{
+			revision: DBMS_MIN_PROTOCOL_VERSION_WITH_CHUNKED_PACKETS,
+			expected: []byte{
+				3, 'k', 'e', 'y',
+				7, 'c', 'h', 'u', 'n', 'k', 'e', 'd',
+				10, 'n', 'o', 't', 'c', 'h', 'u', 'n', 'k', 'e', 'd',
+			},
+		},
+	} {
+		var buffer bytes.Buffer
+		if assert.NoError(t, addendum.Encode(binary.NewEncoder(&buffer), asset.revision)) {
+			assert.Equal(t, asset.expected, append([]byte{}, buffer.Bytes()...))
+		}
+	}
+}
+
+func TestNegotiateChunked(t *testing.T) {
+	for _, asset := range []struct {
+		srv, client string
+		chunked     bool
+		err         bool
+	}{
+		{srv: NotChunkedOptional, client: NotChunkedOptional, chunked: false},
+		{srv: ChunkedOptional, client: NotChunkedOptional, chunked: false},
+		{srv: NotChunkedOptional, client: Chunked, chunked: true},
+		{srv: Chunked, client: NotChunkedOptional, chunked: true},
+		{srv: NotChunked, client: ChunkedOptional, chunked: false},
+		{srv: Chunked, client: Chunked, chunked: true},
+		{srv: Chunked, client: NotChunked, err: true},
+	} {
+		chunked, err := NegotiateChunked(asset.srv, asset.client)
+		if asset.err {
+			assert.Error(t, err)
+			continue
+		}
+		if assert.NoError(t, err) {
+			assert.Equal(t, asset.chunked, chunked, "%s/%s", asset.srv, asset.client)
+		}
+	}
+}
@@ -13,6 +13,8 @@ type ProfileInfo struct {
 	AppliedLimit              bool
 	RowsBeforeLimit           uint64
 	CalculatedRowsBeforeLimit bool
+	AppliedAggregation        bool
+	RowsBeforeAggregation     uint64
 }
 
 func (p *ProfileInfo) Decode(decoder *binary.Decoder, revision uint64) (err error) {
@@ -34,6 +36,14 @@ func (p *ProfileInfo) Decode(decoder *binary.Decoder, revision uint64) (err erro
 	if p.CalculatedRowsBeforeLimit, err = decoder.Bool(); err != nil {
 		return err
 	}
+	if revision >= DBMS_MIN_REVISION_WITH_ROWS_BEFORE_AGGREGATION {
+		if p.AppliedAggregation, err = decoder.Bool(); err != nil {
+			return err
+		}
+		if p.RowsBeforeAggregation, err = decoder.Uvarint(); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -2,6 +2,7 @@ package proto
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/ClickHouse/clickhouse-go/v2/lib/binary"
 )
@@ -10,8 +11,10 @@ type Progress struct {
 	Rows       uint64
 	Bytes      uint64
 	TotalRows  uint64
+	TotalBytes uint64
 	WroteRows  uint64
 	WroteBytes uint64
+	Elapsed    time.Duration
 	withClient bool
 }
 
@@ -25,6 +28,11 @@ func (p *Progress) Decode(decoder *binary.Decoder, revision uint64) (err error)
 	if p.TotalRows, err = decoder.Uvarint(); err != nil {
 		return err
 	}
+	if revision >= DBMS_MIN_PROTOCOL_VERSION_WITH_TOTAL_BYTES_IN_PROGRESS {
+		if p.TotalBytes, err = decoder.Uvarint(); err != nil {
+			return err
+		}
+	}
 	if revision >= DBMS_MIN_REVISION_WITH_CLIENT_WRITE_INFO {
 		p.withClient = true
 		if p.WroteRows, err = decoder.Uvarint(); err != nil {
@@ -34,6 +42,13 @@ func (p *Progress) Decode(decoder *binary.Decoder, revision uint64) (err error)
 			return err
 		}
 	}
+	if revision >= DBMS_MIN_PROTOCOL_VERSION_WITH_SERVER_QUERY_TIME_IN_PROGRESS {
+		elapsed, err := decoder.Uvarint()
+		if err != nil {
+			return err
+		}
+		p.Elapsed = time.Duration(elapsed)
+	}
 	return nil
 }
 
@@ -0,0 +1,71 @@
+package proto
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/ClickHouse/clickhouse-go/v2/lib/binary"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProgressDecode(t *testing.T) {
+	for _, asset := range []struct {
+		revision uint64
+		data     []byte
+		expected Progress
+	}{
+		{
+			revision: DBMS_MIN_REVISION_WITH_PARALLEL_REPLICAS,
+			data:     []byte{1, 2, 3, 4, 5},
+			expected: Progress{Rows: 1, Bytes: 2, TotalRows: 3, WroteRows: 4, WroteBytes: 5, withClient: true},
+		},
+		{
+			revision: DBMS_MIN_PROTOCOL_VERSION_WITH_SERVER_QUERY_TIME_IN_PROGRESS,
+			data:     []byte{1, 2, 3, 4, 5, 0xe8, 0x07},
+			expected: Progress{Rows: 1, Bytes: 2, TotalRows: 3, WroteRows: 4, WroteBytes: 5, Elapsed: time.Microsecond, withClient: true},
+		},
+		{
+			revision: DBMS_MIN_PROTOCOL_VERSION_WITH_TOTAL_BYTES_IN_PROGRESS,
+			data:     []byte{1, 2, 3, 6, 4, 5, 0xe8, 0x07},
+			expected: Progress{Rows: 1, Bytes: 2, TotalRows: 3, TotalBytes: 6, WroteRows: 4, WroteBytes: 5, Elapsed: time.Microsecond, withClient: true},
+		},
+	} {
+		var (
+			progress Progress
+			reader   = bytes.NewReader(asset.data)
+		)
+		if assert.NoError(t, progress.Decode(binary.NewDecoder(reader), asset.revision)) {
+			assert.Equal(t, 0, reader.Len())
+			assert.Equal(t, asset.expected, progress)
+		}
+	}
+}
+
+func TestProfileInfoDecode(t *testing.T) {
+	for _, asset := range []struct {
+		revision uint64
+		data     []byte
+		expected ProfileInfo
+	}{
+		{
+			revision: DBMS_MIN_REVISION_WITH_PARALLEL_REPLICAS,
+			data:     []byte{1, 2, 3, 1, 4, 1},
+			expected: ProfileInfo{Rows: 1, Blocks: 2, Bytes: 3, AppliedLimit: true, RowsBeforeLimit: 4, CalculatedRowsBeforeLimit: true},
+		},
+		{
+			revision: DBMS_MIN_REVISION_WITH_ROWS_BEFORE_AGGREGATION,
+			data:     []byte{1, 2, 3, 1, 4, 1, 1, 5},
+			expected: ProfileInfo{Rows: 1, Blocks: 2, Bytes: 3, AppliedLimit: true, RowsBeforeLimit: 4, CalculatedRowsBeforeLimit: true, AppliedAggregation: true, RowsBeforeAggregation: 5},
+		},
+	} {
+		var (
+			info   ProfileInfo
+			reader = bytes.NewReader(asset.data)
+		)
+		if assert.NoError(t, info.Decode(binary.NewDecoder(reader), asset.revision)) {
+			assert.Equal(t, 0, reader.Len())
+			assert.Equal(t, asset.expected, info)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package proto
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/ClickHouse/clickhouse-go/v2/lib/binary"
 	"go.opentelemetry.io/otel/trace"
@@ -19,6 +20,7 @@ type Query struct {
 	Body           string
 	QuotaKey       string
 	Settings       Settings
+	Parameters     Parameters
 	Compression    bool
 	InitialUser    string
 	InitialAddress string
@@ -45,7 +47,16 @@ func (q *Query) Encode(encoder *binary.Encoder, revision uint64) error {
 		encoder.Byte(StateComplete)
 		encoder.Bool(q.Compression)
 	}
-	return encoder.String(q.Body)
+	if err := encoder.String(q.Body); err != nil {
+		return err
+	}
+	if revision >= DBMS_MIN_PROTOCOL_VERSION_WITH_PARAMETERS {
+		if err := q.Parameters.Encode(encoder, revision); err != nil {
+			return err
+		}
+		return encoder.String("" /* empty string is a marker of the end of parameters */)
+	}
+	return nil
 }
 
 func (q *Query) encodeClientInfo(encoder *binary.Encoder, revision uint64) error {
@@ -140,3 +151,35 @@ func (s *Setting) encode(encoder *binary.Encoder, revision uint64) error {
 	}
 	return encoder.String(fmt.Sprint(s.Value))
 }
+
+// Parameters are the values of the {name:Type} placeholders of the query.
+// They are sent as custom settings, the server parses the values according to the placeholder types.
+type Parameters []Parameter
+
+type Parameter struct {
+	Key   string
+	Value string
+}
+
+// settingFlagCustom marks the settings unknown to the server (the query parameters).
+const settingFlagCustom = 0x02
+
+func (p Parameters) Encode(encoder *binary.Encoder, revision uint64) error {
+	for _, p := range p {
+		if err := encoder.String(p.Key); err != nil {
+			return err
+		}
+		if err := encoder.Uvarint(settingFlagCustom); err != nil {
+			return err
+		}
+		// the value of a custom setting is a dumped Field, a string literal here
+		if err := encoder.String(quote(p.Value)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func quote(v string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(v) + "'"
+}
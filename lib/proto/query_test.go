@@ -0,0 +1,40 @@
+package proto
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ClickHouse/clickhouse-go/v2/lib/binary"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryParameters(t *testing.T) {
+	encode := func(q *Query, revision uint64) []byte {
+		var (
+			buf     bytes.Buffer
+			encoder = binary.NewEncoder(&buf)
+		)
+		if assert.NoError(t, q.Encode(encoder, revision)) && assert.NoError(t, encoder.Flush()) {
+			return buf.Bytes()
+		}
+		return nil
+	}
+	q := Query{
+		Body: "SELECT {s:String}",
+		Parameters: Parameters{
+			{Key: "s", Value: `it's`},
+		},
+	}
+	var (
+		withParameters    = encode(&q, DBMS_MIN_PROTOCOL_VERSION_WITH_PARAMETERS)
+		withoutParameters = encode(&q, DBMS_MIN_PROTOCOL_VERSION_WITH_PARAMETERS-1)
+	)
+	expected := []byte{
+		17, 'S', 'E', 'L', 'E', 'C', 'T', ' ', '{', 's', ':', 'S', 't', 'r', 'i', 'n', 'g', '}',
+		1, 's', 0x02, 7, '\'', 'i', 't', '\\', '\'', 's', '\'',
+		0,
+	}
+	if assert.True(t, bytes.HasSuffix(withParameters, expected)) {
+		assert.True(t, bytes.HasSuffix(withoutParameters, expected[:18]))
+	}
+}
@@ -2,13 +2,46 @@ package proto
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/ClickHouse/clickhouse-go/v2/lib/binary"
 )
 
 type TableColumns struct {
-	First  string
-	Second string
+	First   string
+	Second  string
+	Columns []ColumnDescription
+}
+
+// ColumnDescription is a column of the table the data is inserted into.
+type ColumnDescription struct {
+	Name string
+	Type string
+	// DefaultKind is DEFAULT, MATERIALIZED, ALIAS or EPHEMERAL, empty for the columns without a default expression.
+	DefaultKind       string
+	DefaultExpression string
+	Comment           string
+	Codec             string
+	TTL               string
+}
+
+// HasDefault reports whether the server computes the value of the column when it is not inserted.
+func (c *ColumnDescription) HasDefault() bool {
+	switch c.DefaultKind {
+	case "DEFAULT", "EPHEMERAL":
+		return true
+	}
+	return false
+}
+
+// Insertable reports whether the column accepts the inserted values.
+func (c *ColumnDescription) Insertable() bool {
+	switch c.DefaultKind {
+	case "MATERIALIZED", "ALIAS":
+		return false
+	}
+	return true
 }
 
 func (t *TableColumns) Decode(decoder *binary.Decoder, revision uint64) (err error) {
@@ -21,6 +54,120 @@ func (t *TableColumns) Decode(decoder *binary.Decoder, revision uint64) (err err
 	return nil
 }
 
+// ParseColumns parses the description of the columns sent by the server, the Columns are left nil on error.
+func (t *TableColumns) ParseColumns() (err error) {
+	if t.Columns, err = parseColumnsDescription(t.Second); err != nil {
+		t.Columns = nil
+		return fmt.Errorf("could not parse table columns: %v", err)
+	}
+	return nil
+}
+
 func (t *TableColumns) String() string {
 	return fmt.Sprintf("first=%s, second=%s", t.First, t.Second)
 }
+
+// parseColumnsDescription parses the text of ColumnsDescription::toString:
+//
+//	columns format version: 1
+//	2 columns:
+//	`id` UInt64
+//	`name` String	DEFAULT	'unknown'	COMMENT 'the name'
+func parseColumnsDescription(text string) ([]ColumnDescription, error) {
+	lines := strings.Split(text, "\n")
+	if len(lines) < 2 || lines[0] != "columns format version: 1" {
+		return nil, fmt.Errorf("unexpected header %q", lines[0])
+	}
+	size, err := strconv.Atoi(strings.TrimSuffix(lines[1], " columns:"))
+	if err != nil {
+		return nil, fmt.Errorf("unexpected header %q", lines[1])
+	}
+	if len(lines) < size+2 {
+		return nil, fmt.Errorf("expected %d columns, got %d", size, len(lines)-2)
+	}
+	columns := make([]ColumnDescription, 0, size)
+	for _, line := range lines[2 : size+2] {
+		column, err := parseColumnDescription(line)
+		if err != nil {
+			return nil, err
+		}
+		columns = append(columns, column)
+	}
+	return columns, nil
+}
+
+func parseColumnDescription(line string) (column ColumnDescription, err error) {
+	if column.Name, line, err = readBackQuoted(line); err != nil {
+		return column, err
+	}
+	if !strings.HasPrefix(line, " ") {
+		return column, fmt.Errorf("unexpected description of the column %s", column.Name)
+	}
+	fields := strings.Split(line[1:], "\t")
+	column.Type = unescape(fields[0])
+	for i := 1; i < len(fields); i++ {
+		switch field := fields[i]; {
+		case field == "DEFAULT", field == "MATERIALIZED", field == "ALIAS", field == "EPHEMERAL":
+			if i+1 == len(fields) {
+				return column, fmt.Errorf("missing default expression of the column %s", column.Name)
+			}
+			column.DefaultKind, column.DefaultExpression = field, unescape(fields[i+1])
+			i++
+		case strings.HasPrefix(field, "COMMENT "):
+			// the comment is a quoted string
+			comment := unescape(strings.TrimPrefix(field, "COMMENT "))
+			column.Comment = unescape(strings.TrimSuffix(strings.TrimPrefix(comment, "'"), "'"))
+		case strings.HasPrefix(field, "CODEC("):
+			column.Codec = unescape(field)
+		case strings.HasPrefix(field, "TTL "):
+			column.TTL = unescape(strings.TrimPrefix(field, "TTL "))
+		}
+	}
+	return column, nil
+}
+
+func readBackQuoted(s string) (string, string, error) {
+	if !strings.HasPrefix(s, "`") {
+		return "", "", fmt.Errorf("expected a back quoted name in %q", s)
+	}
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '`':
+			return unescape(s[1:i]), s[i+1:], nil
+		}
+	}
+	return "", "", fmt.Errorf("unterminated back quoted name in %q", s)
+}
+
+func unescape(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 == len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 'b':
+			b.WriteByte('\b')
+		case 'f':
+			b.WriteByte('\f')
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 't':
+			b.WriteByte('\t')
+		case '0':
+			b.WriteByte(0)
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,50 @@
+package proto
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ClickHouse/clickhouse-go/v2/lib/binary"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTableColumnsDecode(t *testing.T) {
+	const description = "columns format version: 1\n" +
+		"5 columns:\n" +
+		"`id` UInt64\tCODEC(Delta(8), LZ4)\n" +
+		"`name` String\tDEFAULT\t\\'unknown\\'\tCOMMENT \\'the user\\\\\\'s name\\'\n" +
+		"`name_len` UInt64\tMATERIALIZED\tlength(name)\n" +
+		"`upper name` String\tALIAS\tupper(name)\n" +
+		"`ts` DateTime(\\'UTC\\')\tEPHEMERAL\tnow()\tTTL ts + toIntervalDay(1)\n"
+	var (
+		buffer  bytes.Buffer
+		encoder = binary.NewEncoder(&buffer)
+		columns TableColumns
+	)
+	encoder.String("")
+	encoder.String(description)
+	if assert.NoError(t, columns.Decode(binary.NewDecoder(&buffer), DBMS_TCP_PROTOCOL_VERSION)) && assert.NoError(t, columns.ParseColumns()) {
+		assert.Equal(t, []ColumnDescription{
+			{Name: "id", Type: "UInt64", Codec: "CODEC(Delta(8), LZ4)"},
+			{Name: "name", Type: "String", DefaultKind: "DEFAULT", DefaultExpression: "'unknown'", Comment: "the user's name"},
+			{Name: "name_len", Type: "UInt64", DefaultKind: "MATERIALIZED", DefaultExpression: "length(name)"},
+			{Name: "upper name", Type: "String", DefaultKind: "ALIAS", DefaultExpression: "upper(name)"},
+			{Name: "ts", Type: "DateTime('UTC')", DefaultKind: "EPHEMERAL", DefaultExpression: "now()", TTL: "ts + toIntervalDay(1)"},
+		}, columns.Columns)
+		var (
+			hasDefault []bool
+			insertable []bool
+		)
+		for _, c := range columns.Columns {
+			hasDefault, insertable = append(hasDefault, c.HasDefault()), append(insertable, c.Insertable())
+		}
+		assert.Equal(t, []bool{false, true, false, false, true}, hasDefault)
+		assert.Equal(t, []bool{true, true, false, false, true}, insertable)
+	}
+	for _, invalid := range []string{"", "columns format version: 2\n0 columns:\n", "columns format version: 1\n2 columns:\n`id` UInt64\n", "columns format version: 1\n1 columns:\nid UInt64\n"} {
+		columns := TableColumns{Second: invalid}
+		if assert.Error(t, columns.ParseColumns(), invalid) {
+			assert.Nil(t, columns.Columns)
+		}
+	}
+}
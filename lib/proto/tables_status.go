@@ -0,0 +1,124 @@
+package proto
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ClickHouse/clickhouse-go/v2/lib/binary"
+	"github.com/ClickHouse/clickhouse-go/v2/lib/column"
+	"github.com/google/uuid"
+)
+
+// DBMS_CLUSTER_PROCESSING_PROTOCOL_VERSION is the version of the read task responses.
+const DBMS_CLUSTER_PROCESSING_PROTOCOL_VERSION = 1
+
+type QualifiedTableName struct {
+	Database string
+	Table    string
+}
+
+type TablesStatusRequest struct {
+	Tables []QualifiedTableName
+}
+
+func (r *TablesStatusRequest) Encode(encoder *binary.Encoder) error {
+	if err := encoder.Uvarint(uint64(len(r.Tables))); err != nil {
+		return err
+	}
+	for _, t := range r.Tables {
+		if err := encoder.String(t.Database); err != nil {
+			return err
+		}
+		if err := encoder.String(t.Table); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type TableStatus struct {
+	Database     string
+	Table        string
+	IsReplicated bool
+	// AbsoluteDelay is the replication delay of a replicated table.
+	AbsoluteDelay time.Duration
+	IsReadonly    bool
+}
+
+type TablesStatusResponse struct {
+	Tables []TableStatus
+}
+
+func (r *TablesStatusResponse) Decode(decoder *binary.Decoder, revision uint64) error {
+	size, err := decoder.Uvarint()
+	if err != nil {
+		return err
+	}
+	r.Tables = make([]TableStatus, 0, size)
+	for i := 0; i < int(size); i++ {
+		var status TableStatus
+		if status.Database, err = decoder.String(); err != nil {
+			return err
+		}
+		if status.Table, err = decoder.String(); err != nil {
+			return err
+		}
+		if status.IsReplicated, err = decoder.Bool(); err != nil {
+			return err
+		}
+		if status.IsReplicated {
+			delay, err := decoder.Uvarint()
+			if err != nil {
+				return err
+			}
+			status.AbsoluteDelay = time.Duration(delay) * time.Second
+			if revision >= DBMS_MIN_REVISION_WITH_TABLE_READ_ONLY_CHECK {
+				if status.IsReadonly, err = decoder.Bool(); err != nil {
+					return err
+				}
+			}
+		}
+		r.Tables = append(r.Tables, status)
+	}
+	return nil
+}
+
+func (s TableStatus) String() string {
+	if !s.IsReplicated {
+		return fmt.Sprintf("%s.%s", s.Database, s.Table)
+	}
+	return fmt.Sprintf("%s.%s replicated (delay %s, readonly %t)", s.Database, s.Table, s.AbsoluteDelay, s.IsReadonly)
+}
+
+// PartUUIDs are the UUIDs of the parts read by the query (sent with allow_experimental_query_deduplication).
+type PartUUIDs []uuid.UUID
+
+func (p *PartUUIDs) Decode(decoder *binary.Decoder) error {
+	size, err := decoder.Uvarint()
+	if err != nil {
+		return err
+	}
+	var uuids column.UUID
+	if size != 0 {
+		if err := uuids.Decode(decoder, int(size)); err != nil {
+			return err
+		}
+	}
+	*p = make(PartUUIDs, 0, size)
+	for i := 0; i < int(size); i++ {
+		*p = append(*p, uuids.Row(i, false).(uuid.UUID))
+	}
+	return nil
+}
+
+// ReadTaskResponse is the answer to a read task request, an empty task means that there are no more tasks.
+type ReadTaskResponse struct {
+	Task string
+}
+
+func (r *ReadTaskResponse) Encode(encoder *binary.Encoder) error {
+	if err := encoder.Uvarint(DBMS_CLUSTER_PROCESSING_PROTOCOL_VERSION); err != nil {
+		return err
+	}
+	return encoder.String(r.Task)
+}
@@ -0,0 +1,69 @@
+package proto
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/ClickHouse/clickhouse-go/v2/lib/binary"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTablesStatusRequestEncode(t *testing.T) {
+	var (
+		buffer  bytes.Buffer
+		request = TablesStatusRequest{
+			Tables: []QualifiedTableName{{Database: "db", Table: "t"}},
+		}
+	)
+	if assert.NoError(t, request.Encode(binary.NewEncoder(&buffer))) {
+		assert.Equal(t, []byte{1, 2, 'd', 'b', 1, 't'}, buffer.Bytes())
+	}
+}
+
+func TestTablesStatusResponseDecode(t *testing.T) {
+	header := []byte{
+		2,
+		2, 'd', 'b', 2, 't', '1', 0, // not replicated
+		2, 'd', 'b', 2, 't', '2', 1, 10, // replicated, 10 seconds delay
+	}
+	for _, asset := range []struct {
+		revision uint64
+		data     []byte
+		readonly bool
+	}{
+		{revision: DBMS_MIN_REVISION_WITH_SSH_AUTHENTICATION, data: header},
+		{revision: DBMS_MIN_REVISION_WITH_TABLE_READ_ONLY_CHECK, data: append(append([]byte{}, header...), 1), readonly: true},
+	} {
+		var (
+			response TablesStatusResponse
+			reader   = bytes.NewReader(asset.data)
+		)
+		if assert.NoError(t, response.Decode(binary.NewDecoder(reader), asset.revision)) {
+			assert.Equal(t, 0, reader.Len())
+			assert.Equal(t, []TableStatus{
+				{Database: "db", Table: "t1"},
+				{Database: "db", Table: "t2", IsReplicated: true, AbsoluteDelay: 10 * time.Second, IsReadonly: asset.readonly},
+			}, response.Tables)
+		}
+	}
+}
+
+func TestPartUUIDsDecode(t *testing.T) {
+	var (
+		uuids  PartUUIDs
+		id     = uuid.New()
+		reader = bytes.NewReader(append([]byte{1}, id[:]...))
+	)
+	if assert.NoError(t, uuids.Decode(binary.NewDecoder(reader))) {
+		assert.Equal(t, PartUUIDs{id}, uuids)
+	}
+}
+
+func TestReadTaskResponseEncode(t *testing.T) {
+	var buffer bytes.Buffer
+	if assert.NoError(t, (&ReadTaskResponse{}).Encode(binary.NewEncoder(&buffer))) {
+		assert.Equal(t, []byte{1, 0}, buffer.Bytes())
+	}
+}
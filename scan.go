@@ -5,11 +5,20 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"strings"
 
+	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
 	"github.com/ClickHouse/clickhouse-go/v2/lib/proto"
 )
 
 func (ch *clickhouse) Select(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	return selectStructs(dest, func() (driver.Rows, error) {
+		return ch.Query(ctx, query, args...)
+	})
+}
+
+// selectStructs scans the rows of the query into the slice of structs the dest points to.
+func selectStructs(dest interface{}, query func() (driver.Rows, error)) error {
 	value := reflect.ValueOf(dest)
 	if value.Kind() != reflect.Ptr {
 		return &OpError{
@@ -29,7 +38,7 @@ func (ch *clickhouse) Select(ctx context.Context, dest interface{}, query string
 	}
 	var (
 		base      = direct.Type().Elem()
-		rows, err = ch.Query(ctx, query, args...)
+		rows, err = query()
 	)
 	if err != nil {
 		return err
@@ -64,19 +73,19 @@ func scan(block *proto.Block, row int, dest ...interface{}) error {
 	return nil
 }
 
-func structToScannableValues(columns []string, dest interface{}) ([]interface{}, error) {
+func structToScannableValues(columns []string, dest interface{}) ([]interface{}, nestedFields, error) {
 	var (
 		v = reflect.ValueOf(dest)
 		t = reflect.TypeOf(dest)
 	)
 	if v.Kind() != reflect.Ptr {
-		return nil, &OpError{
+		return nil, nil, &OpError{
 			Op:  "ScanStruct",
 			Err: errors.New("must pass a pointer, not a value, to ScanStruct destination"),
 		}
 	}
 	if v.IsNil() {
-		return nil, &OpError{
+		return nil, nil, &OpError{
 			Op:  "ScanStruct",
 			Err: errors.New("nil pointer passed to ScanStruct destination"),
 		}
@@ -85,7 +94,7 @@ func structToScannableValues(columns []string, dest interface{}) ([]interface{},
 		t = t.Elem()
 	}
 	if v.Kind() != reflect.Struct {
-		return nil, &OpError{
+		return nil, nil, &OpError{
 			Op:  "ScanStruct",
 			Err: errors.New("ScanStruct expects a struct dest"),
 		}
@@ -93,26 +102,128 @@ func structToScannableValues(columns []string, dest interface{}) ([]interface{},
 	var (
 		names  = make(map[string]interface{}, len(columns))
 		values = make([]interface{}, 0, len(columns))
+		nested = make(map[string]*nestedField)
+		fields nestedFields
 	)
 	for i := 0; i < v.NumField(); i++ {
 		var (
 			f    = t.Field(i)
-			name = f.Name
+			name = fieldName(f)
 		)
-		if tn := f.Tag.Get("ch"); len(tn) != 0 {
-			name = tn
-		}
 		names[name] = v.Field(i).Addr().Interface()
+		if f.Type.Kind() == reflect.Slice && f.Type.Elem().Kind() == reflect.Struct {
+			nested[name] = &nestedField{
+				name:  name,
+				value: v.Field(i),
+			}
+		}
 	}
 	for _, name := range columns {
-		v, found := names[name]
-		if !found {
-			return nil, &OpError{
+		if v, found := names[name]; found {
+			values = append(values, v)
+			continue
+		}
+		// the columns of Nested(a T, b U) are flattened into n.a and n.b (flatten_nested = 1)
+		if n := strings.Index(name, "."); n > 0 {
+			if field, found := nested[name[:n]]; found {
+				if v, found := field.column(name[n+1:]); found {
+					if len(field.columns) == 1 {
+						fields = append(fields, field)
+					}
+					values = append(values, v)
+					continue
+				}
+			}
+		}
+		return nil, nil, &OpError{
+			Op:  "ScanStruct",
+			Err: fmt.Errorf("missing destination name %q in %T", name, dest),
+		}
+	}
+	return values, fields, nil
+}
+
+func fieldName(f reflect.StructField) string {
+	if tn := f.Tag.Get("ch"); len(tn) != 0 {
+		return tn
+	}
+	return f.Name
+}
+
+// nestedField maps a slice of structs onto the flattened columns of a Nested data structure.
+type nestedField struct {
+	name    string
+	value   reflect.Value
+	columns []nestedColumn
+}
+
+type nestedColumn struct {
+	name  string
+	field int
+	slice reflect.Value
+}
+
+func (n *nestedField) column(name string) (interface{}, bool) {
+	elem := n.value.Type().Elem()
+	for i := 0; i < elem.NumField(); i++ {
+		if f := elem.Field(i); len(f.PkgPath) == 0 && fieldName(f) == name {
+			slice := reflect.New(reflect.SliceOf(f.Type))
+			n.columns = append(n.columns, nestedColumn{
+				name:  name,
+				field: i,
+				slice: slice.Elem(),
+			})
+			return slice.Interface(), true
+		}
+	}
+	return nil, false
+}
+
+// split copies the struct fields into the column slices.
+func (n *nestedField) split() {
+	for _, c := range n.columns {
+		slice := reflect.MakeSlice(c.slice.Type(), n.value.Len(), n.value.Len())
+		for i := 0; i < n.value.Len(); i++ {
+			slice.Index(i).Set(n.value.Index(i).Field(c.field))
+		}
+		c.slice.Set(slice)
+	}
+}
+
+// join builds the slice of structs from the scanned column slices.
+func (n *nestedField) join() error {
+	size := n.columns[0].slice.Len()
+	for _, c := range n.columns[1:] {
+		if c.slice.Len() != size {
+			return &OpError{
 				Op:  "ScanStruct",
-				Err: fmt.Errorf("missing destination name %q in %T", name, dest),
+				Err: fmt.Errorf("mismatched array sizes of nested columns %s.%s and %s.%s", n.name, n.columns[0].name, n.name, c.name),
 			}
 		}
-		values = append(values, v)
 	}
-	return values, nil
+	value := reflect.MakeSlice(n.value.Type(), size, size)
+	for _, c := range n.columns {
+		for i := 0; i < size; i++ {
+			value.Index(i).Field(c.field).Set(c.slice.Index(i))
+		}
+	}
+	n.value.Set(value)
+	return nil
+}
+
+type nestedFields []*nestedField
+
+func (fields nestedFields) split() {
+	for _, field := range fields {
+		field.split()
+	}
+}
+
+func (fields nestedFields) join() error {
+	for _, field := range fields {
+		if err := field.join(); err != nil {
+			return err
+		}
+	}
+	return nil
 }
@@ -0,0 +1,65 @@
+package clickhouse
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStructToScannableValuesNested(t *testing.T) {
+	type item struct {
+		A uint8
+		B string `ch:"b"`
+	}
+	type data struct {
+		ID    uint64 `ch:"id"`
+		Items []item `ch:"n"`
+	}
+	columns := []string{"id", "n.A", "n.b"}
+	t.Run("split", func(t *testing.T) {
+		values, nested, err := structToScannableValues(columns, &data{
+			ID: 42,
+			Items: []item{
+				{A: 1, B: "a"},
+				{A: 2, B: "b"},
+			},
+		})
+		if assert.NoError(t, err) && assert.Len(t, values, 3) {
+			nested.split()
+			assert.Equal(t, uint64(42), *values[0].(*uint64))
+			assert.Equal(t, []uint8{1, 2}, *values[1].(*[]uint8))
+			assert.Equal(t, []string{"a", "b"}, *values[2].(*[]string))
+		}
+	})
+	t.Run("join", func(t *testing.T) {
+		var result data
+		values, nested, err := structToScannableValues(columns, &result)
+		if assert.NoError(t, err) && assert.Len(t, values, 3) {
+			*values[0].(*uint64) = 42
+			*values[1].(*[]uint8) = []uint8{1, 2}
+			*values[2].(*[]string) = []string{"a", "b"}
+			if assert.NoError(t, nested.join()) {
+				assert.Equal(t, data{
+					ID: 42,
+					Items: []item{
+						{A: 1, B: "a"},
+						{A: 2, B: "b"},
+					},
+				}, result)
+			}
+		}
+	})
+	t.Run("mismatched sizes", func(t *testing.T) {
+		var result data
+		values, nested, err := structToScannableValues(columns, &result)
+		if assert.NoError(t, err) {
+			*values[1].(*[]uint8) = []uint8{1, 2}
+			*values[2].(*[]string) = []string{"a"}
+			assert.Error(t, nested.join())
+		}
+	})
+	t.Run("missing column", func(t *testing.T) {
+		_, _, err := structToScannableValues([]string{"id", "n.C"}, &data{})
+		assert.Error(t, err)
+	})
+}
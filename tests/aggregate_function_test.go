@@ -0,0 +1,106 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAggregateFunction(t *testing.T) {
+	var (
+		ctx       = context.Background()
+		conn, err = clickhouse.Open(&clickhouse.Options{
+			Addr: []string{"127.0.0.1:9000"},
+			Auth: clickhouse.Auth{
+				Database: "default",
+				Username: "default",
+				Password: "",
+			},
+			Compression: &clickhouse.Compression{
+				Method: clickhouse.CompressionLZ4,
+			},
+			//Debug: true,
+		})
+	)
+	if assert.NoError(t, err) {
+		const ddl = `
+			CREATE TABLE %s (
+				  Col1 AggregateFunction(uniq, UInt64)
+				, Col2 AggregateFunction(sum, UInt32)
+				, Col3 AggregateFunction(max, String)
+				, Col4 AggregateFunction(groupArray, UInt16)
+				, Col5 AggregateFunction(quantile(0.5), Float64)
+			) Engine Memory
+		`
+		for _, table := range []string{"test_aggregate_function", "test_aggregate_function_copy"} {
+			if err := conn.Exec(ctx, "DROP TABLE IF EXISTS "+table); !assert.NoError(t, err) {
+				return
+			}
+			if err := conn.Exec(ctx, fmt.Sprintf(ddl, table)); !assert.NoError(t, err) {
+				return
+			}
+		}
+		const insert = `
+			INSERT INTO test_aggregate_function
+			SELECT
+				  uniqState(number)
+				, sumState(toUInt32(number))
+				, maxState(toString(number))
+				, groupArrayState(toUInt16(number))
+				, quantileState(0.5)(toFloat64(number))
+			FROM numbers(10)
+		`
+		if err := conn.Exec(ctx, insert); !assert.NoError(t, err) {
+			return
+		}
+		var states [5][]byte
+		if err := conn.QueryRow(ctx, "SELECT * FROM test_aggregate_function").Scan(&states[0], &states[1], &states[2], &states[3], &states[4]); !assert.NoError(t, err) {
+			return
+		}
+		if batch, err := conn.PrepareBatch(ctx, "INSERT INTO test_aggregate_function_copy"); assert.NoError(t, err) {
+			if err := batch.Append(states[0], states[1], states[2], states[3], states[4]); !assert.NoError(t, err) {
+				return
+			}
+			if assert.NoError(t, batch.Send()) {
+				var (
+					uniq     uint64
+					sum      uint64
+					max      string
+					array    []uint16
+					quantile float64
+				)
+				const query = `
+					SELECT
+						  uniqMerge(Col1)
+						, sumMerge(Col2)
+						, maxMerge(Col3)
+						, groupArrayMerge(Col4)
+						, quantileMerge(0.5)(Col5)
+					FROM test_aggregate_function_copy
+				`
+				if err := conn.QueryRow(ctx, query).Scan(&uniq, &sum, &max, &array, &quantile); assert.NoError(t, err) {
+					assert.Equal(t, uint64(10), uniq)
+					assert.Equal(t, uint64(45), sum)
+					assert.Equal(t, "9", max)
+					assert.Equal(t, []uint16{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, array)
+					assert.Equal(t, 4.5, quantile)
+				}
+			}
+		}
+		var (
+			sum   uint64
+			max   *string
+			array []uint16
+		)
+		if err := conn.QueryRow(ctx, "SELECT Col2, Col3, Col4 FROM test_aggregate_function").Scan(&sum, &max, &array); assert.NoError(t, err) {
+			assert.Equal(t, uint64(45), sum)
+			if assert.NotNil(t, max) {
+				assert.Equal(t, "9", *max)
+			}
+			assert.Equal(t, []uint16{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, array)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAsyncInsert(t *testing.T) {
+	var (
+		ctx       = context.Background()
+		conn, err = clickhouse.Open(&clickhouse.Options{
+			Addr: []string{"127.0.0.1:9000"},
+			Auth: clickhouse.Auth{
+				Database: "default",
+				Username: "default",
+				Password: "",
+			},
+			Compression: &clickhouse.Compression{
+				Method: clickhouse.CompressionLZ4,
+			},
+			//Debug: true,
+		})
+	)
+	if assert.NoError(t, err) {
+		if err := checkMinServerVersion(conn, 21, 11); err != nil {
+			t.Skip(err.Error())
+			return
+		}
+		const ddl = `
+		CREATE TABLE test_async_insert (
+			  Col1 UInt64
+			, Col2 String
+		) Engine MergeTree() ORDER BY tuple()
+		`
+		defer func() {
+			conn.Exec(ctx, "DROP TABLE test_async_insert")
+		}()
+		if err := conn.Exec(ctx, "DROP TABLE IF EXISTS test_async_insert"); assert.NoError(t, err) {
+			if err := conn.Exec(ctx, ddl); assert.NoError(t, err) {
+				for i := 0; i < 10; i++ {
+					if err := conn.AsyncInsert(ctx, "INSERT INTO test_async_insert VALUES ($1, $2)", true, uint64(i), fmt.Sprintf("value_%d", i)); !assert.NoError(t, err) {
+						return
+					}
+				}
+				// the query parameters
+				for i := 10; i < 20; i++ {
+					if err := conn.AsyncInsert(ctx, "INSERT INTO test_async_insert VALUES ({col1:UInt64}, {col2:String})", true,
+						clickhouse.Named("col1", uint64(i)),
+						clickhouse.Named("col2", fmt.Sprintf("value_%d", i)),
+					); !assert.NoError(t, err) {
+						return
+					}
+				}
+				var count uint64
+				if err := conn.QueryRow(ctx, "SELECT COUNT() FROM test_async_insert").Scan(&count); assert.NoError(t, err) {
+					assert.Equal(t, uint64(20), count)
+				}
+				var value string
+				if err := conn.QueryRow(ctx, "SELECT Col2 FROM test_async_insert WHERE Col1 = 15").Scan(&value); assert.NoError(t, err) {
+					assert.Equal(t, "value_15", value)
+				}
+			}
+		}
+	}
+}
+
+func TestBufferedInserter(t *testing.T) {
+	var (
+		ctx       = context.Background()
+		conn, err = clickhouse.Open(&clickhouse.Options{
+			Addr: []string{"127.0.0.1:9000"},
+			Auth: clickhouse.Auth{
+				Database: "default",
+				Username: "default",
+				Password: "",
+			},
+			Compression: &clickhouse.Compression{
+				Method: clickhouse.CompressionLZ4,
+			},
+			//Debug: true,
+		})
+	)
+	if assert.NoError(t, err) {
+		const ddl = `
+		CREATE TABLE test_buffered_inserter (
+			  Col1 UInt64
+			, Col2 String
+		) Engine Memory
+		`
+		defer func() {
+			conn.Exec(ctx, "DROP TABLE test_buffered_inserter")
+		}()
+		if err := conn.Exec(ctx, "DROP TABLE IF EXISTS test_buffered_inserter"); assert.NoError(t, err) {
+			if err := conn.Exec(ctx, ddl); assert.NoError(t, err) {
+				inserter := clickhouse.NewBufferedInserter(ctx, conn, clickhouse.BufferedInserterOptions{
+					MaxRows: 100,
+					OnFlush: func(query string, rows int, err error) {
+						assert.NoError(t, err)
+					},
+				})
+				for i := 0; i < 1000; i++ {
+					if err := inserter.Insert("INSERT INTO test_buffered_inserter", uint64(i), fmt.Sprintf("value_%d", i)); !assert.NoError(t, err) {
+						return
+					}
+				}
+				if assert.NoError(t, inserter.Close()) {
+					var count uint64
+					if err := conn.QueryRow(ctx, "SELECT COUNT() FROM test_buffered_inserter").Scan(&count); assert.NoError(t, err) {
+						assert.Equal(t, uint64(1000), count)
+					}
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBatchAbort(t *testing.T) {
+	var (
+		ctx       = context.Background()
+		conn, err = clickhouse.Open(&clickhouse.Options{
+			Addr: []string{"127.0.0.1:9000"},
+			Auth: clickhouse.Auth{
+				Database: "default",
+				Username: "default",
+				Password: "",
+			},
+			Compression: &clickhouse.Compression{
+				Method: clickhouse.CompressionLZ4,
+			},
+			MaxOpenConns: 1,
+			//Debug: true,
+		})
+	)
+	if assert.NoError(t, err) {
+		const ddl = `
+		CREATE TABLE test_batch_abort (
+			Col1 UInt64
+		) Engine Memory
+		`
+		defer func() {
+			conn.Exec(ctx, "DROP TABLE test_batch_abort")
+		}()
+		if err := conn.Exec(ctx, "DROP TABLE IF EXISTS test_batch_abort"); assert.NoError(t, err) {
+			if err := conn.Exec(ctx, ddl); assert.NoError(t, err) {
+				if batch, err := conn.PrepareBatch(ctx, "INSERT INTO test_batch_abort"); assert.NoError(t, err) {
+					if assert.NoError(t, batch.Append(uint64(1))) && assert.NoError(t, batch.Abort()) {
+						assert.Equal(t, clickhouse.ErrBatchAlreadySent, batch.Send())
+						// the only connection of the pool is usable again
+						var count uint64
+						if err := conn.QueryRow(ctx, "SELECT COUNT() FROM test_batch_abort").Scan(&count); assert.NoError(t, err) {
+							assert.Equal(t, uint64(0), count)
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestBatchResetAndClone(t *testing.T) {
+	var (
+		ctx       = context.Background()
+		conn, err = clickhouse.Open(&clickhouse.Options{
+			Addr: []string{"127.0.0.1:9000"},
+			Auth: clickhouse.Auth{
+				Database: "default",
+				Username: "default",
+				Password: "",
+			},
+			Compression: &clickhouse.Compression{
+				Method: clickhouse.CompressionLZ4,
+			},
+			//Debug: true,
+		})
+	)
+	if assert.NoError(t, err) {
+		const ddl = `
+		CREATE TABLE test_batch_reset (
+			  Col1 UInt64
+			, Col2 String
+		) Engine Memory
+		`
+		defer func() {
+			conn.Exec(ctx, "DROP TABLE test_batch_reset")
+		}()
+		if err := conn.Exec(ctx, "DROP TABLE IF EXISTS test_batch_reset"); assert.NoError(t, err) {
+			if err := conn.Exec(ctx, ddl); assert.NoError(t, err) {
+				if batch, err := conn.PrepareBatch(ctx, "INSERT INTO test_batch_reset"); assert.NoError(t, err) {
+					if !assert.NoError(t, batch.Append(uint64(1), "dropped")) || !assert.NoError(t, batch.Reset()) {
+						return
+					}
+					clone, err := batch.Clone()
+					if !assert.NoError(t, err) {
+						return
+					}
+					if !assert.NoError(t, clone.Append(uint64(2), "clone")) || !assert.NoError(t, clone.Send()) {
+						return
+					}
+					if !assert.NoError(t, batch.Append(uint64(3), "batch")) || !assert.NoError(t, batch.Send()) {
+						return
+					}
+					// the batch starts a new INSERT after Send
+					if assert.NoError(t, batch.Reset()) {
+						if assert.NoError(t, batch.Append(uint64(4), "reset")) && assert.NoError(t, batch.Send()) {
+							var count uint64
+							if err := conn.QueryRow(ctx, "SELECT COUNT() FROM test_batch_reset WHERE Col2 != 'dropped'").Scan(&count); assert.NoError(t, err) {
+								assert.Equal(t, uint64(3), count)
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBatchFlush(t *testing.T) {
+	var (
+		ctx       = context.Background()
+		conn, err = clickhouse.Open(&clickhouse.Options{
+			Addr: []string{"127.0.0.1:9000"},
+			Auth: clickhouse.Auth{
+				Database: "default",
+				Username: "default",
+				Password: "",
+			},
+			Compression: &clickhouse.Compression{
+				Method: clickhouse.CompressionLZ4,
+			},
+			//Debug: true,
+		})
+	)
+	if assert.NoError(t, err) {
+		const ddl = `
+		CREATE TABLE test_batch_flush (
+			  Col1 UInt64
+			, Col2 String
+		) Engine Memory
+		`
+		defer func() {
+			conn.Exec(ctx, "DROP TABLE test_batch_flush")
+		}()
+		if err := conn.Exec(ctx, "DROP TABLE IF EXISTS test_batch_flush"); assert.NoError(t, err) {
+			if err := conn.Exec(ctx, ddl); assert.NoError(t, err) {
+				if batch, err := conn.PrepareBatch(ctx, "INSERT INTO test_batch_flush"); assert.NoError(t, err) {
+					for i := 0; i < 1000; i++ {
+						if err := batch.Append(uint64(i), fmt.Sprintf("value_%d", i)); !assert.NoError(t, err) {
+							return
+						}
+						if i%100 == 99 {
+							if err := batch.Flush(); !assert.NoError(t, err) {
+								return
+							}
+						}
+					}
+					if assert.NoError(t, batch.Send()) {
+						var count uint64
+						if err := conn.QueryRow(ctx, "SELECT COUNT() FROM test_batch_flush").Scan(&count); assert.NoError(t, err) {
+							assert.Equal(t, uint64(1000), count)
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestBatchAutoFlush(t *testing.T) {
+	var (
+		ctx       = context.Background()
+		conn, err = clickhouse.Open(&clickhouse.Options{
+			Addr: []string{"127.0.0.1:9000"},
+			Auth: clickhouse.Auth{
+				Database: "default",
+				Username: "default",
+				Password: "",
+			},
+			Compression: &clickhouse.Compression{
+				Method: clickhouse.CompressionLZ4,
+			},
+			//Debug: true,
+		})
+	)
+	if assert.NoError(t, err) {
+		const ddl = `
+		CREATE TABLE test_batch_auto_flush (
+			  Col1 UInt64
+			, Col2 String
+		) Engine Memory
+		`
+		defer func() {
+			conn.Exec(ctx, "DROP TABLE test_batch_auto_flush")
+		}()
+		if err := conn.Exec(ctx, "DROP TABLE IF EXISTS test_batch_auto_flush"); assert.NoError(t, err) {
+			if err := conn.Exec(ctx, ddl); assert.NoError(t, err) {
+				ctx := clickhouse.Context(ctx, clickhouse.WithAutoFlush(100, 1024))
+				if batch, err := conn.PrepareBatch(ctx, "INSERT INTO test_batch_auto_flush"); assert.NoError(t, err) {
+					type data struct {
+						Col1 uint64
+						Col2 string
+					}
+					for i := 0; i < 1000; i++ {
+						if err := batch.AppendStruct(&data{Col1: uint64(i), Col2: fmt.Sprintf("value_%d", i)}); !assert.NoError(t, err) {
+							return
+						}
+					}
+					if assert.NoError(t, batch.Send()) {
+						var count uint64
+						if err := conn.QueryRow(ctx, "SELECT COUNT() FROM test_batch_auto_flush").Scan(&count); assert.NoError(t, err) {
+							assert.Equal(t, uint64(1000), count)
+						}
+					}
+				}
+			}
+		}
+	}
+}
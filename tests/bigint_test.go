@@ -0,0 +1,187 @@
+package tests
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBigInt(t *testing.T) {
+	var (
+		ctx       = context.Background()
+		conn, err = clickhouse.Open(&clickhouse.Options{
+			Addr: []string{"127.0.0.1:9000"},
+			Auth: clickhouse.Auth{
+				Database: "default",
+				Username: "default",
+				Password: "",
+			},
+			Compression: &clickhouse.Compression{
+				Method: clickhouse.CompressionLZ4,
+			},
+			//Debug: true,
+		})
+	)
+	if assert.NoError(t, err) {
+		if err := checkMinServerVersion(conn, 21, 1); err != nil {
+			t.Skip(err.Error())
+			return
+		}
+		const ddl = `
+			CREATE TABLE test_bigint (
+				  Col1 Int128
+				, Col2 UInt128
+				, Col3 Int256
+				, Col4 UInt256
+				, Col5 Nullable(Int128)
+				, Col6 Array(UInt256)
+				, Col7 Array(Nullable(Int256))
+				, Col8 Map(String, UInt128)
+				, Col9 Map(UInt256, String)
+			) Engine Memory
+		`
+		if err := conn.Exec(ctx, "DROP TABLE IF EXISTS test_bigint"); assert.NoError(t, err) {
+			if err := conn.Exec(ctx, ddl); assert.NoError(t, err) {
+				if batch, err := conn.PrepareBatch(ctx, "INSERT INTO test_bigint"); assert.NoError(t, err) {
+					var (
+						col1Data, _ = new(big.Int).SetString("-170141183460469231731687303715884105728", 10)
+						col2Data, _ = new(big.Int).SetString("340282366920938463463374607431768211455", 10)
+						col3Data, _ = new(big.Int).SetString("-57896044618658097711785492504343953926634992332820282019728792003956564819968", 10)
+						col4Data, _ = new(big.Int).SetString("115792089237316195423570985008687907853269984665640564039457584007913129639935", 10)
+						col6Data    = []big.Int{*big.NewInt(1), *big.NewInt(2)}
+						col7Data    = []*big.Int{big.NewInt(-1), nil, big.NewInt(42)}
+						col8Data    = map[string]big.Int{
+							"key": *big.NewInt(42),
+						}
+						// big.Int is not comparable, the keys are the decimal representation of the values
+						col9Data = map[string]string{
+							"115792089237316195423570985008687907853269984665640564039457584007913129639935": "max",
+						}
+					)
+					if err := batch.Append(col1Data, col2Data, col3Data, col4Data, nil, col6Data, col7Data, col8Data, col9Data); !assert.NoError(t, err) {
+						return
+					}
+					if assert.NoError(t, batch.Send()) {
+						var (
+							col1 big.Int
+							col2 big.Int
+							col3 big.Int
+							col4 big.Int
+							col5 *big.Int
+							col6 []big.Int
+							col7 []*big.Int
+							col8 map[string]big.Int
+							col9 map[string]string
+						)
+						if err := conn.QueryRow(ctx, "SELECT * FROM test_bigint").Scan(&col1, &col2, &col3, &col4, &col5, &col6, &col7, &col8, &col9); assert.NoError(t, err) {
+							assert.Equal(t, 0, col1Data.Cmp(&col1))
+							assert.Equal(t, 0, col2Data.Cmp(&col2))
+							assert.Equal(t, 0, col3Data.Cmp(&col3))
+							assert.Equal(t, 0, col4Data.Cmp(&col4))
+							assert.Nil(t, col5)
+							if assert.Len(t, col6, 2) {
+								assert.Equal(t, int64(1), col6[0].Int64())
+								assert.Equal(t, int64(2), col6[1].Int64())
+							}
+							if assert.Len(t, col7, 3) {
+								assert.Equal(t, int64(-1), col7[0].Int64())
+								assert.Nil(t, col7[1])
+								assert.Equal(t, int64(42), col7[2].Int64())
+							}
+							if v, ok := col8["key"]; assert.True(t, ok) {
+								assert.Equal(t, int64(42), v.Int64())
+							}
+							assert.Equal(t, col9Data, col9)
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestColumnarBigInt(t *testing.T) {
+	var (
+		ctx       = context.Background()
+		conn, err = clickhouse.Open(&clickhouse.Options{
+			Addr: []string{"127.0.0.1:9000"},
+			Auth: clickhouse.Auth{
+				Database: "default",
+				Username: "default",
+				Password: "",
+			},
+			Compression: &clickhouse.Compression{
+				Method: clickhouse.CompressionLZ4,
+			},
+			//Debug: true,
+		})
+	)
+	if assert.NoError(t, err) {
+		if err := checkMinServerVersion(conn, 21, 1); err != nil {
+			t.Skip(err.Error())
+			return
+		}
+		const ddl = `
+			CREATE TABLE test_bigint (
+				  ID   UInt64
+				, Col1 Int256
+				, Col2 Nullable(UInt128)
+				, Col3 LowCardinality(UInt256)
+			) Engine Memory
+		`
+		if err := conn.Exec(ctx, "DROP TABLE IF EXISTS test_bigint"); assert.NoError(t, err) {
+			if err := conn.Exec(clickhouse.Context(ctx, clickhouse.WithSettings(clickhouse.Settings{
+				"allow_suspicious_low_cardinality_types": 1,
+			})), ddl); assert.NoError(t, err) {
+				if batch, err := conn.PrepareBatch(ctx, "INSERT INTO test_bigint"); assert.NoError(t, err) {
+					var (
+						id       []uint64
+						col1Data []big.Int
+						col2Data []*big.Int
+						col3Data []big.Int
+					)
+					for i := 0; i < 1000; i++ {
+						id = append(id, uint64(i))
+						col1Data = append(col1Data, *big.NewInt(int64(-i)))
+						if i%2 == 0 {
+							col2Data = append(col2Data, big.NewInt(int64(i)))
+						} else {
+							col2Data = append(col2Data, nil)
+						}
+						col3Data = append(col3Data, *big.NewInt(int64(i % 10)))
+					}
+					{
+						if err := batch.Column(0).Append(id); !assert.NoError(t, err) {
+							return
+						}
+						if err := batch.Column(1).Append(col1Data); !assert.NoError(t, err) {
+							return
+						}
+						if err := batch.Column(2).Append(col2Data); !assert.NoError(t, err) {
+							return
+						}
+						if err := batch.Column(3).Append(col3Data); !assert.NoError(t, err) {
+							return
+						}
+					}
+					if assert.NoError(t, batch.Send()) {
+						var (
+							col1 big.Int
+							col2 *big.Int
+							col3 big.Int
+						)
+						if err := conn.QueryRow(ctx, "SELECT Col1, Col2, Col3 FROM test_bigint WHERE ID = $1", 11).Scan(&col1, &col2, &col3); assert.NoError(t, err) {
+							if assert.Nil(t, col2) {
+								assert.Equal(t, int64(-11), col1.Int64())
+								assert.Equal(t, int64(1), col3.Int64())
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRowsColumnTypes(t *testing.T) {
+	var (
+		ctx       = context.Background()
+		conn, err = clickhouse.Open(&clickhouse.Options{
+			Addr: []string{"127.0.0.1:9000"},
+			Auth: clickhouse.Auth{
+				Database: "default",
+				Username: "default",
+				Password: "",
+			},
+			Compression: &clickhouse.Compression{
+				Method: clickhouse.CompressionLZ4,
+			},
+			//Debug: true,
+		})
+	)
+	if assert.NoError(t, err) {
+		const query = `
+		SELECT
+			  toNullable(toDecimal64(1.5, 2))                 AS Col1
+			, toDateTime('2022-01-01 00:00:00', 'Asia/Tokyo') AS Col2
+			, CAST('a', 'Enum8(\'a\' = 1, \'b\' = 2)')        AS Col3
+			, map('key', [1, 2])                              AS Col4
+		`
+		if rows, err := conn.Query(ctx, query); assert.NoError(t, err) {
+			defer rows.Close()
+			types := rows.ColumnTypes()
+			if assert.Len(t, types, 4) {
+				assert.Equal(t, "Col1", types[0].Name())
+				assert.True(t, types[0].Nullable())
+				if precision, scale, ok := types[0].PrecisionScale(); assert.True(t, ok) {
+					assert.Equal(t, int64(18), precision)
+					assert.Equal(t, int64(2), scale)
+				}
+				if tz := types[1].Timezone(); assert.NotNil(t, tz) {
+					assert.Equal(t, "Asia/Tokyo", tz.String())
+				}
+				assert.Equal(t, map[string]int{"a": 1, "b": 2}, types[2].EnumValues())
+				if elements := types[3].Elements(); assert.Len(t, elements, 2) {
+					assert.Equal(t, "String", elements[0].DatabaseTypeName())
+					if values := elements[1].Elements(); assert.Len(t, values, 1) {
+						assert.Equal(t, "UInt8", values[0].DatabaseTypeName())
+					}
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/ClickHouse/clickhouse-go/v2/lib/compress"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompression(t *testing.T) {
+	for _, method := range []compress.Method{clickhouse.CompressionLZ4, clickhouse.CompressionLZ4HC, clickhouse.CompressionZSTD} {
+		for _, serverMethod := range []string{"lz4", "zstd"} {
+			var (
+				ctx = clickhouse.Context(context.Background(), clickhouse.WithSettings(clickhouse.Settings{
+					"network_compression_method": serverMethod,
+				}))
+				conn, err = clickhouse.Open(&clickhouse.Options{
+					Addr: []string{"127.0.0.1:9000"},
+					Auth: clickhouse.Auth{
+						Database: "default",
+						Username: "default",
+						Password: "",
+					},
+					Compression: &clickhouse.Compression{
+						Method:    method,
+						Level:     3,
+						BlockSize: 64 * 1024,
+					},
+					//Debug: true,
+				})
+			)
+			if assert.NoError(t, err) {
+				const ddl = `
+					CREATE TABLE test_compression (
+						  Col1 UInt64
+						, Col2 String
+					) Engine Memory
+				`
+				if err := conn.Exec(ctx, "DROP TABLE IF EXISTS test_compression"); assert.NoError(t, err) {
+					if err := conn.Exec(ctx, ddl); assert.NoError(t, err) {
+						if batch, err := conn.PrepareBatch(ctx, "INSERT INTO test_compression"); assert.NoError(t, err) {
+							for i := 0; i < 100_000; i++ {
+								if err := batch.Append(uint64(i), fmt.Sprintf("value_%d", i)); !assert.NoError(t, err) {
+									return
+								}
+							}
+							if assert.NoError(t, batch.Send()) {
+								var (
+									count uint64
+									sum   uint64
+								)
+								if err := conn.QueryRow(ctx, "SELECT COUNT(), SUM(Col1) FROM test_compression WHERE Col2 LIKE 'value_%'").Scan(&count, &sum); assert.NoError(t, err) {
+									assert.Equal(t, uint64(100_000), count, "%s/%s", method, serverMethod)
+									assert.Equal(t, uint64(4999950000), sum, "%s/%s", method, serverMethod)
+								}
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+}
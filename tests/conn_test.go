@@ -2,6 +2,8 @@ package tests
 
 import (
 	"context"
+	"net"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -56,6 +58,35 @@ func TestConnFailover(t *testing.T) {
 		}
 	}
 }
+
+func TestConnFailoverHostsHealth(t *testing.T) {
+	conn, err := clickhouse.Open(&clickhouse.Options{
+		Addr: []string{
+			"127.0.0.1:9001",
+			"127.0.0.1:9000",
+		},
+		Auth: clickhouse.Auth{
+			Database: "default",
+			Username: "default",
+			Password: "",
+		},
+		ConnOpenStrategy: clickhouse.ConnOpenLeastConnections,
+		//	Debug: true,
+	})
+	if assert.NoError(t, err) {
+		if err := conn.Ping(context.Background()); assert.NoError(t, err) {
+			stats := conn.Stats()
+			if assert.Len(t, stats.Hosts, 2) {
+				assert.False(t, stats.Hosts[0].Healthy)
+				assert.Equal(t, 1, stats.Hosts[0].Failures)
+				assert.Error(t, stats.Hosts[0].LastError)
+				assert.True(t, stats.Hosts[1].Healthy)
+				assert.Equal(t, 1, stats.Hosts[1].Open)
+			}
+		}
+	}
+}
+
 func TestPingDeadline(t *testing.T) {
 	conn, err := clickhouse.Open(&clickhouse.Options{
 		Addr: []string{"127.0.0.1:9000"},
@@ -77,3 +108,26 @@ func TestPingDeadline(t *testing.T) {
 		}
 	}
 }
+
+func TestConnDialContext(t *testing.T) {
+	var dialed int32
+	conn, err := clickhouse.Open(&clickhouse.Options{
+		Addr: []string{"clickhouse:9000"},
+		Auth: clickhouse.Auth{
+			Database: "default",
+			Username: "default",
+			Password: "",
+		},
+		DialContext: func(ctx context.Context, addr string) (net.Conn, error) {
+			atomic.AddInt32(&dialed, 1)
+			var d net.Dialer
+			// the address is resolved by the dialer
+			return d.DialContext(ctx, "tcp", "127.0.0.1:9000")
+		},
+	})
+	if assert.NoError(t, err) {
+		if err := conn.Ping(context.Background()); assert.NoError(t, err) {
+			assert.Equal(t, int32(1), atomic.LoadInt32(&dialed))
+		}
+	}
+}
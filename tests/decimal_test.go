@@ -137,3 +137,70 @@ func TestNullableDecimal(t *testing.T) {
 		}
 	}
 }
+
+func TestDecimal256(t *testing.T) {
+	var (
+		ctx       = context.Background()
+		conn, err = clickhouse.Open(&clickhouse.Options{
+			Addr: []string{"127.0.0.1:9000"},
+			Auth: clickhouse.Auth{
+				Database: "default",
+				Username: "default",
+				Password: "",
+			},
+			Compression: &clickhouse.Compression{
+				Method: clickhouse.CompressionLZ4,
+			},
+			//Debug: true,
+		})
+	)
+	if assert.NoError(t, err) {
+		if err := checkMinServerVersion(conn, 21, 1); err != nil {
+			t.Skip(err.Error())
+			return
+		}
+		const ddl = `
+		CREATE TABLE test_decimal (
+			  Col1 Decimal256(10)
+			, Col2 Decimal(60, 20)
+			, Col3 Nullable(Decimal(76, 2))
+			, Col4 Array(Decimal256(5))
+		) Engine Memory
+	`
+		if err := conn.Exec(ctx, "DROP TABLE IF EXISTS test_decimal"); assert.NoError(t, err) {
+			if err := conn.Exec(ctx, ddl); assert.NoError(t, err) {
+				if batch, err := conn.PrepareBatch(ctx, "INSERT INTO test_decimal"); assert.NoError(t, err) {
+					var (
+						col1Data = decimal.RequireFromString("-123456789012345678901234567890.0123456789")
+						col2Data = decimal.RequireFromString("1234567890123456789012345678901234567890.01234567890123456789")
+						col3Data = decimal.RequireFromString("-12345678901234567890123456789012345678901234567890.42")
+						col4Data = []decimal.Decimal{
+							decimal.New(25, 0),
+							decimal.New(-30, 2),
+						}
+					)
+					if err := batch.Append(col1Data, col2Data, col3Data, col4Data); !assert.NoError(t, err) {
+						return
+					}
+					if assert.NoError(t, batch.Send()) {
+						var (
+							col1 decimal.Decimal
+							col2 decimal.Decimal
+							col3 *decimal.Decimal
+							col4 []decimal.Decimal
+						)
+						if err := conn.QueryRow(ctx, "SELECT * FROM test_decimal").Scan(&col1, &col2, &col3, &col4); assert.NoError(t, err) {
+							assert.True(t, col1Data.Equal(col1))
+							assert.True(t, col2Data.Equal(col2))
+							assert.True(t, col3Data.Equal(*col3))
+							if assert.Len(t, col4, 2) {
+								assert.True(t, col4Data[0].Equal(col4[0]))
+								assert.True(t, col4Data[1].Equal(col4[1]))
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+}
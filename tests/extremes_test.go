@@ -0,0 +1,49 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithExtremes(t *testing.T) {
+	var (
+		ctx       = context.Background()
+		conn, err = clickhouse.Open(&clickhouse.Options{
+			Addr: []string{"127.0.0.1:9000"},
+			Auth: clickhouse.Auth{
+				Database: "default",
+				Username: "default",
+				Password: "",
+			},
+			Compression: &clickhouse.Compression{
+				Method: clickhouse.CompressionLZ4,
+			},
+			//Debug: true,
+		})
+	)
+	if assert.NoError(t, err) {
+		ctx := clickhouse.Context(ctx, clickhouse.WithSettings(clickhouse.Settings{
+			"extremes": 1,
+		}))
+		if rows, err := conn.Query(ctx, "SELECT number FROM system.numbers LIMIT 100"); assert.NoError(t, err) {
+			var count int
+			for rows.Next() {
+				var n uint64
+				if !assert.NoError(t, rows.Scan(&n)) {
+					return
+				}
+				count++
+			}
+			if assert.NoError(t, rows.Err()) && assert.Equal(t, 100, count) {
+				var min, max uint64
+				if assert.NoError(t, rows.Extremes(&min, &max)) {
+					assert.Equal(t, uint64(0), min)
+					assert.Equal(t, uint64(99), max)
+				}
+			}
+		}
+	}
+}
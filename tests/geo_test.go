@@ -0,0 +1,187 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/ClickHouse/clickhouse-go/v2/lib/geo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGeo(t *testing.T) {
+	var (
+		ctx       = context.Background()
+		conn, err = clickhouse.Open(&clickhouse.Options{
+			Addr: []string{"127.0.0.1:9000"},
+			Auth: clickhouse.Auth{
+				Database: "default",
+				Username: "default",
+				Password: "",
+			},
+			Compression: &clickhouse.Compression{
+				Method: clickhouse.CompressionLZ4,
+			},
+			Settings: clickhouse.Settings{
+				"allow_experimental_geo_types": 1,
+			},
+			//Debug: true,
+		})
+	)
+	if assert.NoError(t, err) {
+		if err := checkMinServerVersion(conn, 21, 8); err != nil {
+			t.Skip(err.Error())
+			return
+		}
+		const ddl = `
+			CREATE TABLE test_geo (
+				  Col1 Point
+				, Col2 Ring
+				, Col3 Polygon
+				, Col4 MultiPolygon
+				, Col5 Array(Point)
+			) Engine Memory
+		`
+		if err := conn.Exec(ctx, "DROP TABLE IF EXISTS test_geo"); assert.NoError(t, err) {
+			if err := conn.Exec(ctx, ddl); assert.NoError(t, err) {
+				if batch, err := conn.PrepareBatch(ctx, "INSERT INTO test_geo"); assert.NoError(t, err) {
+					var (
+						col1Data = geo.Point{X: 10, Y: 20}
+						col2Data = geo.Ring{
+							{X: 0, Y: 0},
+							{X: 10, Y: 0},
+							{X: 10, Y: 10},
+							{X: 0, Y: 10},
+						}
+						col3Data = geo.Polygon{
+							col2Data,
+							{
+								{X: 2, Y: 2},
+								{X: 3, Y: 2},
+								{X: 3, Y: 3},
+							},
+						}
+						col4Data = geo.MultiPolygon{
+							col3Data,
+							{col2Data},
+						}
+						col5Data = []geo.Point{
+							{X: 1, Y: 2},
+							{X: 3, Y: 4},
+						}
+					)
+					if err := batch.Append(col1Data, col2Data, col3Data, col4Data, col5Data); !assert.NoError(t, err) {
+						return
+					}
+					if assert.NoError(t, batch.Send()) {
+						var (
+							col1 geo.Point
+							col2 geo.Ring
+							col3 geo.Polygon
+							col4 geo.MultiPolygon
+							col5 []geo.Point
+						)
+						if err := conn.QueryRow(ctx, "SELECT * FROM test_geo").Scan(&col1, &col2, &col3, &col4, &col5); assert.NoError(t, err) {
+							assert.Equal(t, col1Data, col1)
+							assert.Equal(t, col2Data, col2)
+							assert.Equal(t, col3Data, col3)
+							assert.Equal(t, col4Data, col4)
+							assert.Equal(t, col5Data, col5)
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestColumnarGeo(t *testing.T) {
+	var (
+		ctx       = context.Background()
+		conn, err = clickhouse.Open(&clickhouse.Options{
+			Addr: []string{"127.0.0.1:9000"},
+			Auth: clickhouse.Auth{
+				Database: "default",
+				Username: "default",
+				Password: "",
+			},
+			Compression: &clickhouse.Compression{
+				Method: clickhouse.CompressionLZ4,
+			},
+			Settings: clickhouse.Settings{
+				"allow_experimental_geo_types": 1,
+			},
+			//Debug: true,
+		})
+	)
+	if assert.NoError(t, err) {
+		if err := checkMinServerVersion(conn, 21, 8); err != nil {
+			t.Skip(err.Error())
+			return
+		}
+		const ddl = `
+			CREATE TABLE test_geo (
+				  ID   UInt64
+				, Col1 Point
+				, Col2 Ring
+				, Col3 Polygon
+				, Col4 MultiPolygon
+			) Engine Memory
+		`
+		if err := conn.Exec(ctx, "DROP TABLE IF EXISTS test_geo"); assert.NoError(t, err) {
+			if err := conn.Exec(ctx, ddl); assert.NoError(t, err) {
+				if batch, err := conn.PrepareBatch(ctx, "INSERT INTO test_geo"); assert.NoError(t, err) {
+					var (
+						id       []uint64
+						col1Data []geo.Point
+						col2Data []geo.Ring
+						col3Data []geo.Polygon
+						col4Data []geo.MultiPolygon
+					)
+					for i := 0; i < 1000; i++ {
+						var (
+							point = geo.Point{X: float64(i), Y: float64(i + 1)}
+							ring  = geo.Ring{point, {X: float64(i + 2), Y: float64(i + 3)}}
+						)
+						id = append(id, uint64(i))
+						col1Data = append(col1Data, point)
+						col2Data = append(col2Data, ring)
+						col3Data = append(col3Data, geo.Polygon{ring, ring})
+						col4Data = append(col4Data, geo.MultiPolygon{{ring}, {ring, ring}})
+					}
+					{
+						if err := batch.Column(0).Append(id); !assert.NoError(t, err) {
+							return
+						}
+						if err := batch.Column(1).Append(col1Data); !assert.NoError(t, err) {
+							return
+						}
+						if err := batch.Column(2).Append(col2Data); !assert.NoError(t, err) {
+							return
+						}
+						if err := batch.Column(3).Append(col3Data); !assert.NoError(t, err) {
+							return
+						}
+						if err := batch.Column(4).Append(col4Data); !assert.NoError(t, err) {
+							return
+						}
+					}
+					if assert.NoError(t, batch.Send()) {
+						var (
+							col1 geo.Point
+							col2 geo.Ring
+							col3 geo.Polygon
+							col4 geo.MultiPolygon
+						)
+						if err := conn.QueryRow(ctx, "SELECT Col1, Col2, Col3, Col4 FROM test_geo WHERE ID = $1", 11).Scan(&col1, &col2, &col3, &col4); assert.NoError(t, err) {
+							assert.Equal(t, col1Data[11], col1)
+							assert.Equal(t, col2Data[11], col2)
+							assert.Equal(t, col3Data[11], col3)
+							assert.Equal(t, col4Data[11], col4)
+						}
+					}
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestObject(t *testing.T) {
+	var (
+		ctx       = context.Background()
+		conn, err = clickhouse.Open(&clickhouse.Options{
+			Addr: []string{"127.0.0.1:9000"},
+			Auth: clickhouse.Auth{
+				Database: "default",
+				Username: "default",
+				Password: "",
+			},
+			Compression: &clickhouse.Compression{
+				Method: clickhouse.CompressionLZ4,
+			},
+			Settings: clickhouse.Settings{
+				"allow_experimental_object_type": 1,
+			},
+			//Debug: true,
+		})
+	)
+	if assert.NoError(t, err) {
+		if err := checkMinServerVersion(conn, 22, 3); err != nil {
+			t.Skip(err.Error())
+			return
+		}
+		const ddl = `
+			CREATE TABLE test_object (
+				  ID    UInt64
+				, Event Object('json')
+			) Engine Memory
+		`
+		type user struct {
+			Name string `json:"name"`
+			Age  int64  `json:"age"`
+		}
+		type event struct {
+			Type string   `json:"type"`
+			User user     `json:"user"`
+			Tags []string `json:"tags"`
+		}
+		if err := conn.Exec(ctx, "DROP TABLE IF EXISTS test_object"); assert.NoError(t, err) {
+			if err := conn.Exec(ctx, ddl); assert.NoError(t, err) {
+				if batch, err := conn.PrepareBatch(ctx, "INSERT INTO test_object"); assert.NoError(t, err) {
+					if err := batch.Append(uint64(1), &event{Type: "login", User: user{Name: "A", Age: 42}, Tags: []string{"web"}}); !assert.NoError(t, err) {
+						return
+					}
+					if err := batch.Append(uint64(2), map[string]interface{}{"type": "logout", "user": map[string]interface{}{"name": "B"}}); !assert.NoError(t, err) {
+						return
+					}
+					if err := batch.Append(uint64(3), `{"type": "click", "tags": ["a", "b"]}`); !assert.NoError(t, err) {
+						return
+					}
+					if assert.NoError(t, batch.Send()) {
+						var (
+							e1 event
+							e2 map[string]interface{}
+						)
+						if err := conn.QueryRow(ctx, "SELECT Event FROM test_object WHERE ID = 1").Scan(&e1); assert.NoError(t, err) {
+							assert.Equal(t, event{Type: "login", User: user{Name: "A", Age: 42}, Tags: []string{"web"}}, e1)
+						}
+						if err := conn.QueryRow(ctx, "SELECT Event FROM test_object WHERE ID = 2").Scan(&e2); assert.NoError(t, err) {
+							assert.Equal(t, "logout", e2["type"])
+							if user, ok := e2["user"].(map[string]interface{}); assert.True(t, ok) {
+								assert.Equal(t, "B", user["name"])
+							}
+						}
+						var events []struct {
+							ID    uint64 `ch:"ID"`
+							Event event  `ch:"Event"`
+						}
+						if err := conn.Select(ctx, &events, "SELECT ID, Event FROM test_object ORDER BY ID"); assert.NoError(t, err) && assert.Len(t, events, 3) {
+							assert.Equal(t, "click", events[2].Event.Type)
+							assert.Equal(t, []string{"a", "b"}, events[2].Event.Tags)
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestJSON(t *testing.T) {
+	var (
+		ctx       = context.Background()
+		conn, err = clickhouse.Open(&clickhouse.Options{
+			Addr: []string{"127.0.0.1:9000"},
+			Auth: clickhouse.Auth{
+				Database: "default",
+				Username: "default",
+				Password: "",
+			},
+			Compression: &clickhouse.Compression{
+				Method: clickhouse.CompressionLZ4,
+			},
+			Settings: clickhouse.Settings{
+				"allow_experimental_json_type": 1,
+			},
+			//Debug: true,
+		})
+	)
+	if assert.NoError(t, err) {
+		if err := checkMinServerVersion(conn, 24, 8); err != nil {
+			t.Skip(err.Error())
+			return
+		}
+		const ddl = `
+			CREATE TABLE test_json (
+				  ID    UInt64
+				, Event JSON(max_dynamic_paths=2, type String)
+			) Engine Memory
+		`
+		type user struct {
+			Name string `json:"name"`
+			Age  int64  `json:"age"`
+		}
+		type event struct {
+			Type string   `json:"type"`
+			User user     `json:"user"`
+			Tags []string `json:"tags"`
+		}
+		if err := conn.Exec(ctx, "DROP TABLE IF EXISTS test_json"); assert.NoError(t, err) {
+			if err := conn.Exec(ctx, ddl); assert.NoError(t, err) {
+				if batch, err := conn.PrepareBatch(ctx, "INSERT INTO test_json"); assert.NoError(t, err) {
+					if err := batch.Append(uint64(1), &event{Type: "login", User: user{Name: "A", Age: 42}, Tags: []string{"web"}}); !assert.NoError(t, err) {
+						return
+					}
+					if err := batch.Append(uint64(2), map[string]interface{}{"type": "logout", "user": map[string]interface{}{"name": "B", "age": "unknown"}}); !assert.NoError(t, err) {
+						return
+					}
+					if err := batch.Append(uint64(3), `{"type": "click", "tags": ["a", "b"], "items": [{"sku": "C-1"}]}`); !assert.NoError(t, err) {
+						return
+					}
+					if assert.NoError(t, batch.Send()) {
+						var (
+							e1 event
+							e2 map[string]interface{}
+							s3 string
+						)
+						if err := conn.QueryRow(ctx, "SELECT Event FROM test_json WHERE ID = 1").Scan(&e1); assert.NoError(t, err) {
+							assert.Equal(t, event{Type: "login", User: user{Name: "A", Age: 42}, Tags: []string{"web"}}, e1)
+						}
+						if err := conn.QueryRow(ctx, "SELECT Event FROM test_json WHERE ID = 2").Scan(&e2); assert.NoError(t, err) {
+							if user, ok := e2["user"].(map[string]interface{}); assert.True(t, ok) {
+								assert.Equal(t, "B", user["name"])
+								assert.Equal(t, "unknown", user["age"])
+							}
+						}
+						if err := conn.QueryRow(ctx, "SELECT Event FROM test_json WHERE ID = 3").Scan(&s3); assert.NoError(t, err) {
+							assert.JSONEq(t, `{"type": "click", "tags": ["a", "b"], "items": [{"sku": "C-1"}]}`, s3)
+						}
+						for id, expected := range map[uint64]string{1: "Int64", 2: "String", 3: "None"} {
+							var dynamicType string
+							if err := conn.QueryRow(ctx, "SELECT dynamicType(Event.user.age) FROM test_json WHERE ID = $1", id).Scan(&dynamicType); assert.NoError(t, err) {
+								assert.Equal(t, expected, dynamicType)
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+}
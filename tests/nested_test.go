@@ -0,0 +1,126 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNested(t *testing.T) {
+	var (
+		ctx       = context.Background()
+		conn, err = clickhouse.Open(&clickhouse.Options{
+			Addr: []string{"127.0.0.1:9000"},
+			Auth: clickhouse.Auth{
+				Database: "default",
+				Username: "default",
+				Password: "",
+			},
+			Compression: &clickhouse.Compression{
+				Method: clickhouse.CompressionLZ4,
+			},
+			//Debug: true,
+		})
+	)
+	if assert.NoError(t, err) {
+		const ddl = `
+		CREATE TABLE test_nested (
+			  ID   UInt64
+			, Col1 Nested(
+				  Key   String
+				, Value UInt32
+			)
+		) Engine Memory
+		`
+		if err := conn.Exec(ctx, "DROP TABLE IF EXISTS test_nested"); assert.NoError(t, err) {
+			if err := conn.Exec(ctx, ddl); assert.NoError(t, err) {
+				type item struct {
+					Key   string
+					Value uint32
+				}
+				type data struct {
+					ID   uint64
+					Col1 []item
+				}
+				if batch, err := conn.PrepareBatch(ctx, "INSERT INTO test_nested"); assert.NoError(t, err) {
+					err := batch.AppendStruct(&data{
+						ID: 1,
+						Col1: []item{
+							{Key: "A", Value: 1},
+							{Key: "B", Value: 2},
+						},
+					})
+					if assert.NoError(t, err) && assert.NoError(t, batch.Send()) {
+						var result data
+						if err := conn.QueryRow(ctx, "SELECT * FROM test_nested").ScanStruct(&result); assert.NoError(t, err) {
+							assert.Equal(t, data{
+								ID: 1,
+								Col1: []item{
+									{Key: "A", Value: 1},
+									{Key: "B", Value: 2},
+								},
+							}, result)
+						}
+					}
+				}
+				if batch, err := conn.PrepareBatch(ctx, "INSERT INTO test_nested"); assert.NoError(t, err) {
+					if err := batch.Append(uint64(2), []string{"A", "B"}, []uint32{1}); assert.NoError(t, err) {
+						assert.Error(t, batch.Send())
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestNestedNotFlattened(t *testing.T) {
+	var (
+		ctx       = context.Background()
+		conn, err = clickhouse.Open(&clickhouse.Options{
+			Addr: []string{"127.0.0.1:9000"},
+			Auth: clickhouse.Auth{
+				Database: "default",
+				Username: "default",
+				Password: "",
+			},
+			Compression: &clickhouse.Compression{
+				Method: clickhouse.CompressionLZ4,
+			},
+			Settings: clickhouse.Settings{
+				"flatten_nested": 0,
+			},
+			//Debug: true,
+		})
+	)
+	if assert.NoError(t, err) {
+		const ddl = `
+		CREATE TABLE test_nested (
+			  Col1 Nested(
+				  Key   String
+				, Value UInt32
+			)
+		) Engine Memory
+		`
+		if err := conn.Exec(ctx, "DROP TABLE IF EXISTS test_nested"); assert.NoError(t, err) {
+			if err := conn.Exec(ctx, ddl); assert.NoError(t, err) {
+				if batch, err := conn.PrepareBatch(ctx, "INSERT INTO test_nested"); assert.NoError(t, err) {
+					col1Data := [][]interface{}{
+						{"A", uint32(1)},
+						{"B", uint32(2)},
+					}
+					if err := batch.Append(col1Data); !assert.NoError(t, err) {
+						return
+					}
+					if assert.NoError(t, batch.Send()) {
+						var col1 [][]interface{}
+						if err := conn.QueryRow(ctx, "SELECT * FROM test_nested").Scan(&col1); assert.NoError(t, err) {
+							assert.Equal(t, col1Data, col1)
+						}
+					}
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryParameters(t *testing.T) {
+	var (
+		ctx       = context.Background()
+		conn, err = clickhouse.Open(&clickhouse.Options{
+			Addr: []string{"127.0.0.1:9000"},
+			Auth: clickhouse.Auth{
+				Database: "default",
+				Username: "default",
+				Password: "",
+			},
+			Compression: &clickhouse.Compression{
+				Method: clickhouse.CompressionLZ4,
+			},
+			//Debug: true,
+		})
+	)
+	if assert.NoError(t, err) {
+		ctx := clickhouse.Context(ctx, clickhouse.WithParameters(clickhouse.Parameters{
+			"num":   "42",
+			"str":   "it's a string",
+			"array": "[1, 2, 3]",
+			"limit": "10",
+		}))
+		var (
+			num   uint8
+			str   string
+			array []uint8
+			count uint64
+		)
+		if err := conn.QueryRow(ctx, "SELECT {num:UInt8}, {str:String}, {array:Array(UInt8)}, count() FROM numbers({limit:UInt64})").Scan(&num, &str, &array, &count); assert.NoError(t, err) {
+			assert.Equal(t, uint8(42), num)
+			assert.Equal(t, "it's a string", str)
+			assert.Equal(t, []uint8{1, 2, 3}, array)
+			assert.Equal(t, uint64(10), count)
+		}
+		if err := conn.QueryRow(ctx, "SELECT {missing:UInt8}").Scan(&num); assert.Error(t, err) {
+			t.Log(err)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRowsNextBlock(t *testing.T) {
+	var (
+		ctx       = context.Background()
+		conn, err = clickhouse.Open(&clickhouse.Options{
+			Addr: []string{"127.0.0.1:9000"},
+			Auth: clickhouse.Auth{
+				Database: "default",
+				Username: "default",
+				Password: "",
+			},
+			Compression: &clickhouse.Compression{
+				Method: clickhouse.CompressionLZ4,
+			},
+			//Debug: true,
+		})
+	)
+	if assert.NoError(t, err) {
+		ctx := clickhouse.Context(ctx, clickhouse.WithSettings(clickhouse.Settings{
+			"max_block_size": 1000,
+		}))
+		if rows, err := conn.Query(ctx, "SELECT toInt64(number), toString(number), toDateTime(number) FROM system.numbers LIMIT 10000"); assert.NoError(t, err) {
+			var (
+				total  int
+				blocks int
+			)
+			for rows.NextBlock() {
+				block := rows.Block()
+				col1, err := block.Column(0)
+				if !assert.NoError(t, err) {
+					return
+				}
+				col2, err := block.Column(1)
+				if !assert.NoError(t, err) {
+					return
+				}
+				col3, err := block.Column(2)
+				if !assert.NoError(t, err) {
+					return
+				}
+				if assert.IsType(t, []int64{}, col1) && assert.IsType(t, []string{}, col2) && assert.IsType(t, []time.Time{}, col3) {
+					assert.Len(t, col1.([]int64), block.Rows())
+					assert.Len(t, col2.([]string), block.Rows())
+					assert.Len(t, col3.([]time.Time), block.Rows())
+					assert.Equal(t, int64(total), col1.([]int64)[0])
+				}
+				total += block.Rows()
+				blocks++
+			}
+			if assert.NoError(t, rows.Err()) {
+				assert.Equal(t, 10000, total)
+				assert.Greater(t, blocks, 1)
+			}
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSession(t *testing.T) {
+	var (
+		ctx       = context.Background()
+		conn, err = clickhouse.Open(&clickhouse.Options{
+			Addr: []string{"127.0.0.1:9000"},
+			Auth: clickhouse.Auth{
+				Database: "default",
+				Username: "default",
+				Password: "",
+			},
+			Compression: &clickhouse.Compression{
+				Method: clickhouse.CompressionLZ4,
+			},
+			MaxOpenConns: 1,
+			//Debug: true,
+		})
+	)
+	if assert.NoError(t, err) {
+		if session, err := conn.Acquire(ctx); assert.NoError(t, err) {
+			if err := session.Exec(ctx, "SET max_threads = 3"); !assert.NoError(t, err) {
+				return
+			}
+			if err := session.Exec(ctx, "CREATE TEMPORARY TABLE test_session (Col1 UInt8)"); !assert.NoError(t, err) {
+				return
+			}
+			if batch, err := session.PrepareBatch(ctx, "INSERT INTO test_session"); assert.NoError(t, err) {
+				if assert.NoError(t, batch.Append(uint8(42))) && assert.NoError(t, batch.Send()) {
+					var col1 uint8
+					if err := session.QueryRow(ctx, "SELECT Col1 FROM test_session").Scan(&col1); assert.NoError(t, err) {
+						assert.Equal(t, uint8(42), col1)
+					}
+					var maxThreads string
+					if err := session.QueryRow(ctx, "SELECT value FROM system.settings WHERE name = 'max_threads'").Scan(&maxThreads); assert.NoError(t, err) {
+						assert.Equal(t, "3", maxThreads)
+					}
+				}
+			}
+			if assert.NoError(t, session.Release()) {
+				assert.Equal(t, clickhouse.ErrSessionReleased, session.Exec(ctx, "SELECT 1"))
+				assert.Equal(t, int64(1), conn.Stats().SessionClosed)
+				assert.Equal(t, int64(0), conn.Stats().ErrorClosed)
+				// the pool has the only connection, its session state is dropped
+				var maxThreads string
+				if err := conn.QueryRow(ctx, "SELECT value FROM system.settings WHERE name = 'max_threads'").Scan(&maxThreads); assert.NoError(t, err) {
+					assert.NotEqual(t, "3", maxThreads)
+				}
+				assert.Error(t, conn.Exec(ctx, "SELECT * FROM test_session"))
+			}
+		}
+	}
+}
@@ -3,10 +3,12 @@ package std
 import (
 	"context"
 	"database/sql"
+	"net"
+	"sync/atomic"
 	"testing"
 	"time"
 
-	_ "github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/ClickHouse/clickhouse-go/v2"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -37,3 +39,18 @@ func TestStdPingDeadline(t *testing.T) {
 		}
 	}
 }
+
+func TestStdDialContext(t *testing.T) {
+	var dialed int32
+	conn := clickhouse.OpenDB(&clickhouse.Options{
+		Addr: []string{"clickhouse:9000"},
+		DialContext: func(ctx context.Context, addr string) (net.Conn, error) {
+			atomic.AddInt32(&dialed, 1)
+			var d net.Dialer
+			return d.DialContext(ctx, "tcp", "127.0.0.1:9000")
+		},
+	})
+	if err := conn.PingContext(context.Background()); assert.NoError(t, err) {
+		assert.Equal(t, int32(1), atomic.LoadInt32(&dialed))
+	}
+}
@@ -0,0 +1,32 @@
+package std
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStdWithExtremes(t *testing.T) {
+	if conn, err := sql.Open("clickhouse", "clickhouse://127.0.0.1:9000?extremes=1"); assert.NoError(t, err) {
+		if rows, err := conn.Query("SELECT number FROM system.numbers LIMIT 100"); assert.NoError(t, err) {
+			var count int
+			for rows.Next() {
+				count++
+			}
+			if assert.Equal(t, 100, count) {
+				// the extremes are the next result set: the minimums and the maximums
+				if assert.True(t, rows.NextResultSet()) {
+					var extremes []uint64
+					for rows.Next() {
+						var n uint64
+						if assert.NoError(t, rows.Scan(&n)) {
+							extremes = append(extremes, n)
+						}
+					}
+					assert.Equal(t, []uint64{0, 99}, extremes)
+				}
+			}
+		}
+	}
+}
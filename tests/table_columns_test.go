@@ -0,0 +1,71 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBatchTableColumns(t *testing.T) {
+	var (
+		ctx       = context.Background()
+		conn, err = clickhouse.Open(&clickhouse.Options{
+			Addr: []string{"127.0.0.1:9000"},
+			Auth: clickhouse.Auth{
+				Database: "default",
+				Username: "default",
+				Password: "",
+			},
+			Compression: &clickhouse.Compression{
+				Method: clickhouse.CompressionLZ4,
+			},
+			//Debug: true,
+		})
+	)
+	if assert.NoError(t, err) {
+		const ddl = `
+			CREATE TABLE test_table_columns (
+				  Col1 UInt64
+				, Col2 String DEFAULT 'unknown' COMMENT 'the name'
+				, Col3 UInt64 MATERIALIZED Col1 * 2
+				, Col4 String ALIAS upper(Col2)
+			) Engine Memory
+		`
+		type data struct {
+			Col1 uint64
+			Col3 uint64
+			Col4 string
+		}
+		if err := conn.Exec(ctx, "DROP TABLE IF EXISTS test_table_columns"); assert.NoError(t, err) {
+			if err := conn.Exec(ctx, ddl); assert.NoError(t, err) {
+				if batch, err := conn.PrepareBatch(ctx, "INSERT INTO test_table_columns"); assert.NoError(t, err) {
+					if columns := batch.TableColumns(); assert.Len(t, columns, 4) {
+						assert.Equal(t, "Col2", columns[1].Name)
+						assert.Equal(t, "DEFAULT", columns[1].DefaultKind)
+						assert.Equal(t, "'unknown'", columns[1].DefaultExpression)
+						assert.Equal(t, "the name", columns[1].Comment)
+						assert.Equal(t, "MATERIALIZED", columns[2].DefaultKind)
+						assert.Equal(t, "ALIAS", columns[3].DefaultKind)
+					}
+					for i := 0; i < 10; i++ {
+						if err := batch.AppendStruct(&data{Col1: uint64(i), Col3: 42, Col4: "ignored"}); !assert.NoError(t, err) {
+							return
+						}
+					}
+					if assert.NoError(t, batch.Send()) {
+						var (
+							col2 string
+							col3 uint64
+						)
+						if err := conn.QueryRow(ctx, "SELECT Col2, Col3 FROM test_table_columns WHERE Col1 = 5").Scan(&col2, &col3); assert.NoError(t, err) {
+							assert.Equal(t, "unknown", col2)
+							assert.Equal(t, uint64(10), col3)
+						}
+					}
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTablesStatus(t *testing.T) {
+	var (
+		ctx       = context.Background()
+		conn, err = clickhouse.Open(&clickhouse.Options{
+			Addr: []string{"127.0.0.1:9000"},
+			Auth: clickhouse.Auth{
+				Database: "default",
+				Username: "default",
+				Password: "",
+			},
+			Compression: &clickhouse.Compression{
+				Method: clickhouse.CompressionLZ4,
+			},
+			//Debug: true,
+		})
+	)
+	if assert.NoError(t, err) {
+		if err := conn.Exec(ctx, "DROP TABLE IF EXISTS test_tables_status"); assert.NoError(t, err) {
+			if err := conn.Exec(ctx, "CREATE TABLE test_tables_status (Col1 UInt8) Engine Memory"); assert.NoError(t, err) {
+				if status, err := conn.TablesStatus(ctx, "test_tables_status", "system.one"); assert.NoError(t, err) && assert.Len(t, status, 2) {
+					assert.Equal(t, "default", status[0].Database)
+					assert.Equal(t, "test_tables_status", status[0].Table)
+					assert.False(t, status[0].IsReplicated)
+					assert.Equal(t, "system", status[1].Database)
+					assert.Equal(t, "one", status[1].Table)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/ClickHouse/clickhouse-go/v2/lib/column"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVariant(t *testing.T) {
+	var (
+		ctx       = context.Background()
+		conn, err = clickhouse.Open(&clickhouse.Options{
+			Addr: []string{"127.0.0.1:9000"},
+			Auth: clickhouse.Auth{
+				Database: "default",
+				Username: "default",
+				Password: "",
+			},
+			Compression: &clickhouse.Compression{
+				Method: clickhouse.CompressionLZ4,
+			},
+			Settings: clickhouse.Settings{
+				"allow_experimental_variant_type": 1,
+			},
+			//Debug: true,
+		})
+	)
+	if assert.NoError(t, err) {
+		if err := checkMinServerVersion(conn, 24, 1); err != nil {
+			t.Skip(err.Error())
+			return
+		}
+		const ddl = `
+			CREATE TABLE test_variant (
+				  ID   UInt64
+				, Col1 Variant(UInt64, String, Array(UInt8))
+			) Engine Memory
+		`
+		if err := conn.Exec(ctx, "DROP TABLE IF EXISTS test_variant"); assert.NoError(t, err) {
+			if err := conn.Exec(ctx, ddl); assert.NoError(t, err) {
+				if batch, err := conn.PrepareBatch(ctx, "INSERT INTO test_variant"); assert.NoError(t, err) {
+					col1Data := []interface{}{
+						uint64(42),
+						"A",
+						nil,
+						[]uint8{1, 2},
+						column.VariantValue{Type: "UInt64", Value: 7},
+					}
+					for i, v := range col1Data {
+						if err := batch.Append(uint64(i), v); !assert.NoError(t, err) {
+							return
+						}
+					}
+					if assert.NoError(t, batch.Send()) {
+						rows, err := conn.Query(ctx, "SELECT Col1 FROM test_variant ORDER BY ID")
+						if !assert.NoError(t, err) {
+							return
+						}
+						var values []column.VariantValue
+						for rows.Next() {
+							var v column.VariantValue
+							if err := rows.Scan(&v); !assert.NoError(t, err) {
+								return
+							}
+							values = append(values, v)
+						}
+						if assert.NoError(t, rows.Err()) {
+							assert.Equal(t, []column.VariantValue{
+								{Type: "UInt64", Value: uint64(42)},
+								{Type: "String", Value: "A"},
+								{},
+								{Type: "Array(UInt8)", Value: []uint8{1, 2}},
+								{Type: "UInt64", Value: uint64(7)},
+							}, values)
+						}
+						var str string
+						if err := conn.QueryRow(ctx, "SELECT Col1 FROM test_variant WHERE ID = 1").Scan(&str); assert.NoError(t, err) {
+							assert.Equal(t, "A", str)
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestDynamic(t *testing.T) {
+	var (
+		ctx       = context.Background()
+		conn, err = clickhouse.Open(&clickhouse.Options{
+			Addr: []string{"127.0.0.1:9000"},
+			Auth: clickhouse.Auth{
+				Database: "default",
+				Username: "default",
+				Password: "",
+			},
+			Compression: &clickhouse.Compression{
+				Method: clickhouse.CompressionLZ4,
+			},
+			Settings: clickhouse.Settings{
+				"allow_experimental_dynamic_type": 1,
+			},
+			//Debug: true,
+		})
+	)
+	if assert.NoError(t, err) {
+		if err := checkMinServerVersion(conn, 24, 7); err != nil {
+			t.Skip(err.Error())
+			return
+		}
+		const ddl = `
+			CREATE TABLE test_dynamic (
+				  ID   UInt64
+				, Col1 Dynamic
+			) Engine Memory
+		`
+		if err := conn.Exec(ctx, "DROP TABLE IF EXISTS test_dynamic"); assert.NoError(t, err) {
+			if err := conn.Exec(ctx, ddl); assert.NoError(t, err) {
+				if batch, err := conn.PrepareBatch(ctx, "INSERT INTO test_dynamic"); assert.NoError(t, err) {
+					var (
+						id       = []uint64{0, 1, 2, 3}
+						col1Data = []interface{}{
+							"A",
+							int64(42),
+							[]string{"B", "C"},
+							column.VariantValue{Type: "UInt8", Value: 1},
+						}
+					)
+					if err := batch.Column(0).Append(id); !assert.NoError(t, err) {
+						return
+					}
+					if err := batch.Column(1).Append(col1Data); !assert.NoError(t, err) {
+						return
+					}
+					if assert.NoError(t, batch.Send()) {
+						var (
+							types []string
+							value column.VariantValue
+						)
+						if err := conn.QueryRow(ctx, "SELECT groupArray(dynamicType(Col1)) FROM (SELECT Col1 FROM test_dynamic ORDER BY ID)").Scan(&types); assert.NoError(t, err) {
+							assert.Equal(t, []string{"String", "Int64", "Array(String)", "UInt8"}, types)
+						}
+						if err := conn.QueryRow(ctx, "SELECT Col1 FROM test_dynamic WHERE ID = 2").Scan(&value); assert.NoError(t, err) {
+							assert.Equal(t, column.VariantValue{Type: "Array(String)", Value: []string{"B", "C"}}, value)
+						}
+					}
+				}
+			}
+		}
+	}
+}